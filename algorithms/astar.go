@@ -0,0 +1,90 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"math"
+)
+
+// computes the shortest distance between two nodes of a graph or hierarchy component with A*
+// returns -1 if a node is missing or endID is not reachable
+func AStar(g *graph.Graph, startID, endID int) float64 {
+	_, cost := AStarPath(g, startID, endID)
+	return cost
+}
+
+// computes a shortest path between two nodes of a graph or hierarchy component with A*,
+// the heuristic is the manhattan distance (octile distance for 8-connected graphs) times the cheapest terrain cost.
+// returns the nodeids from startID to endID (both included) and the path cost, nil and -1 if there is no path
+func AStarPath(g *graph.Graph, startID, endID int) ([]int, float64) {
+	if g == nil {
+		return nil, -1
+	}
+	c := g.Dense()
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return nil, -1
+	}
+
+	scale := c.MinCost // cheapest terrain cost keeps the heuristic admissible
+	endX, endY := g.Coordinates(endID)
+	estimate := func(i int) float64 {
+		x, y := g.Coordinates(c.NodeIDs[i])
+		return scale * gridDistance(abs(x-endX), abs(y-endY), g.Octile())
+	}
+
+	cost := make([]float64, c.Len())
+	prev := make([]int, c.Len())
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	settled := make([]bool, c.Len())
+	queue := graph.CostHeap{{Node: start, Cost: estimate(start)}} // ordered by cost + estimate
+	cost[start] = 0
+	prev[start] = start
+
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(graph.CostItem)
+		// skip outdated entries
+		if settled[item.Node] {
+			continue
+		}
+		if item.Node == end {
+			return tracePath(c, prev, start, end), cost[end]
+		}
+		settled[item.Node] = true
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			newCost := cost[item.Node] + 1
+			if weights != nil {
+				newCost = cost[item.Node] + weights[k]
+			}
+			if !settled[neighbor] && newCost < cost[neighbor] {
+				cost[neighbor] = newCost
+				prev[neighbor] = item.Node
+				heap.Push(&queue, graph.CostItem{Node: neighbor, Cost: newCost + estimate(neighbor)})
+			}
+		}
+	}
+
+	// endID is not reachable from startID
+	return nil, -1
+}
+
+// distance in an empty grid for a difference of dx columns and dy rows
+func gridDistance(dx, dy int, octile bool) float64 {
+	if !octile {
+		return float64(dx + dy)
+	}
+	return float64(max(dx, dy)) + (math.Sqrt2-1)*float64(min(dx, dy))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
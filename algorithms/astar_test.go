@@ -0,0 +1,94 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAStar(t *testing.T) {
+	// random 12x12 grid with obstacles and terrain costs
+	rng := rand.New(rand.NewSource(7))
+	grid := make([][]int, 12)
+	terrain := make([]float64, 144)
+	for y := range grid {
+		grid[y] = make([]int, 12)
+		for x := range grid[y] {
+			grid[y][x] = graph.NodeID(x, y, 12)
+			if rng.Float64() < 0.25 {
+				grid[y][x] = -1
+			}
+			terrain[graph.NodeID(x, y, 12)] = float64(1 + rng.Intn(4))
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		metric *graph.Metric
+	}{
+		{"Manhattan", nil},
+		{"Octile", &graph.Metric{Octile: true}},
+		{"Terrain", &graph.Metric{Cost: terrain}},
+		{"Octile terrain", &graph.Metric{Octile: true, Cost: terrain}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := graph.NewGraph(12, 12)
+			g.Grid = grid
+			g.Metric = tc.metric
+			g.BuildAdjlist()
+
+			for start := range g.AdjList {
+				for end := range g.AdjList {
+					expected := ShortestDistance(g, start, end)
+					path, cost := AStarPath(g, start, end)
+					if math.Abs(cost-expected) > 1e-9 {
+						t.Fatalf("A* %d -> %d is %f, expected %f", start, end, cost, expected)
+					}
+					if expected == -1 {
+						continue
+					}
+					if length, valid := PathLength(g, path); !valid || math.Abs(length-cost) > 1e-9 {
+						t.Fatalf("A* path %d -> %d is not valid: %v", start, end, path)
+					}
+				}
+			}
+			if AStar(g, 0, 999) != -1 {
+				t.Errorf("Expected -1 for unknown node")
+			}
+		})
+	}
+}
+
+func TestAStarInComponent(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+
+	for start := range g.AdjList {
+		for end := range g.AdjList {
+			component := FindSmallestConvexComponent(g, start, end)
+			if got, expected := AStar(component, start, end), ShortestDistance(g, start, end); math.Abs(got-expected) > 1e-9 {
+				t.Errorf("A* %d -> %d in component is %f, expected %f", start, end, got, expected)
+			}
+		}
+	}
+}
+
+func TestGridDistance(t *testing.T) {
+	tests := []struct {
+		dx, dy   int
+		octile   bool
+		expected float64
+	}{
+		{3, 4, false, 7},
+		{3, 4, true, 4 + 3*(math.Sqrt2-1)},
+		{0, 5, true, 5},
+		{0, 0, false, 0},
+	}
+	for _, tt := range tests {
+		if got := gridDistance(tt.dx, tt.dy, tt.octile); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("gridDistance(%d, %d, %v) = %f, expected %f", tt.dx, tt.dy, tt.octile, got, tt.expected)
+		}
+	}
+}
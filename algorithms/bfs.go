@@ -1,5 +1,7 @@
 package algorithms
 
+import "bachelor-project/graph"
+
 // computes the distance between two nodes in a graph with BFS
 func BreadthFirstSearch(adjlist map[int][]int, startID, endID int) int {
 	// base case: start and end are the same node
@@ -40,3 +42,49 @@ func BreadthFirstSearch(adjlist map[int][]int, startID, endID int) int {
 	// return -1 if endID is not reachable from startID
 	return -1
 }
+
+// computes the distance between two nodes with BFS on the compact (CSR) form of a graph
+// visited nodes are tracked in a slice instead of a map
+func BreadthFirstSearchCSR(c *graph.CSR, startID, endID int) int {
+	// base case: start and end are the same node
+	if startID == endID {
+		return 0
+	}
+
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return -1
+	}
+
+	visited := make([]bool, c.Len())
+	queue := make([]int, 0, c.Len())
+	queue = append(queue, start)
+	visited[start] = true
+	head := 0  // pointer
+	depth := 0 // depth for distance
+
+	for head < len(queue) {
+		levelSize := len(queue) - head
+		depth++
+
+		// visit every node of current level
+		for range levelSize {
+			current := queue[head]
+			head++
+
+			for _, neighbor := range c.Adjacent(current) {
+				if neighbor == end {
+					return depth
+				}
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+
+	// return -1 if endID is not reachable from startID
+	return -1
+}
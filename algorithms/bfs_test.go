@@ -1,6 +1,9 @@
 package algorithms
 
-import "testing"
+import (
+	"bachelor-project/graph"
+	"testing"
+)
 
 func TestBreadthFirstSearch(t *testing.T) {
 	/*
@@ -52,3 +55,39 @@ func TestBreadthFirstSearch(t *testing.T) {
 	}
 
 }
+
+func TestBreadthFirstSearchCSR(t *testing.T) {
+	/*
+		0  1  @
+		3  @  5
+		6  7  8
+	*/
+	adjList := map[int][]int{
+		0: {1, 3},
+		1: {0},
+		3: {0, 6},
+		5: {8},
+		6: {3, 7},
+		7: {6, 8},
+		8: {5, 7},
+	}
+	c := graph.NewCSR(adjList)
+
+	if dist := BreadthFirstSearchCSR(c, 1, 1); dist != 0 {
+		t.Errorf("Distance from node 1 to itself should be 0, got %d", dist)
+	}
+	if dist := BreadthFirstSearchCSR(c, 1, 5); dist != 6 {
+		t.Errorf("Distance from node 1 to node 5 should be 6, got %d", dist)
+	}
+	// results must match the map based bfs
+	for _, start := range c.NodeIDs {
+		for _, end := range c.NodeIDs {
+			if BreadthFirstSearchCSR(c, start, end) != BreadthFirstSearch(adjList, start, end) {
+				t.Errorf("CSR and map bfs differ for %d -> %d", start, end)
+			}
+		}
+	}
+	if dist := BreadthFirstSearchCSR(c, 1, 25); dist != -1 {
+		t.Errorf("Distance from node 1 to unreachable node 25 should be -1, got %d", dist)
+	}
+}
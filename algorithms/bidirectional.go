@@ -0,0 +1,134 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"math"
+)
+
+// computes the shortest distance between two nodes of a graph or hierarchy component with a bidirectional search,
+// bidirectional bfs is used for unit costs, bidirectional dijkstra otherwise
+func BidirectionalDistance(g *graph.Graph, startID, endID int) float64 {
+	if g == nil {
+		return -1
+	}
+	if g.Weighted() {
+		return BidirectionalDijkstra(g.Dense(), startID, endID)
+	}
+	return float64(BidirectionalBFS(g.Dense(), startID, endID))
+}
+
+// computes the distance between two nodes with BFS from both ends on the compact (CSR) form of a graph,
+// the side with the smaller frontier expands one level at a time. Returns -1 if endID is not reachable
+func BidirectionalBFS(c *graph.CSR, startID, endID int) int {
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return -1
+	}
+	if start == end {
+		return 0
+	}
+
+	// distance from start (forward) and from end (backward), -1 if not visited
+	dist := [2][]int{make([]int, c.Len()), make([]int, c.Len())}
+	for i := range c.Len() {
+		dist[0][i], dist[1][i] = -1, -1
+	}
+	dist[0][start], dist[1][end] = 0, 0
+	frontier := [2][]int{{start}, {end}}
+
+	for len(frontier[0]) > 0 && len(frontier[1]) > 0 {
+		side := 0
+		if len(frontier[1]) < len(frontier[0]) {
+			side = 1
+		}
+		own, other := dist[side], dist[1-side]
+
+		// expand the whole level, the shortest connection may be found by a later node of the level
+		best := -1
+		next := []int{}
+		for _, current := range frontier[side] {
+			for _, neighbor := range c.Adjacent(current) {
+				if other[neighbor] != -1 {
+					if d := own[current] + 1 + other[neighbor]; best == -1 || d < best {
+						best = d
+					}
+				}
+				if own[neighbor] == -1 {
+					own[neighbor] = own[current] + 1
+					next = append(next, neighbor)
+				}
+			}
+		}
+		if best != -1 {
+			return best
+		}
+		frontier[side] = next
+	}
+
+	// return -1 if endID is not reachable from startID
+	return -1
+}
+
+// computes the cost of a shortest path between two nodes with dijkstra from both ends on the compact (CSR)
+// form of an undirected graph, edges without weights cost 1. Returns -1 if endID is not reachable
+func BidirectionalDijkstra(c *graph.CSR, startID, endID int) float64 {
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return -1
+	}
+	if start == end {
+		return 0
+	}
+
+	cost := [2][]float64{make([]float64, c.Len()), make([]float64, c.Len())}
+	for i := range c.Len() {
+		cost[0][i], cost[1][i] = math.Inf(1), math.Inf(1)
+	}
+	settled := [2][]bool{make([]bool, c.Len()), make([]bool, c.Len())}
+	queue := [2]graph.CostHeap{{{Node: start, Cost: 0}}, {{Node: end, Cost: 0}}}
+	cost[0][start], cost[1][end] = 0, 0
+	best := math.Inf(1)
+
+	for queue[0].Len() > 0 && queue[1].Len() > 0 {
+		// no path through unsettled nodes can be shorter than best
+		if queue[0][0].Cost+queue[1][0].Cost >= best {
+			break
+		}
+		side := 0
+		if queue[1].Len() < queue[0].Len() {
+			side = 1
+		}
+
+		item := heap.Pop(&queue[side]).(graph.CostItem)
+		// skip outdated entries
+		if settled[side][item.Node] {
+			continue
+		}
+		settled[side][item.Node] = true
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			newCost := item.Cost + 1
+			if weights != nil {
+				newCost = item.Cost + weights[k]
+			}
+			if !settled[side][neighbor] && newCost < cost[side][neighbor] {
+				cost[side][neighbor] = newCost
+				heap.Push(&queue[side], graph.CostItem{Node: neighbor, Cost: newCost})
+			}
+			// connection to the other search
+			if total := newCost + cost[1-side][neighbor]; total < best {
+				best = total
+			}
+		}
+	}
+
+	if math.IsInf(best, 1) {
+		// return -1 if endID is not reachable from startID
+		return -1
+	}
+	return best
+}
@@ -0,0 +1,106 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBidirectionalDistance(t *testing.T) {
+	// random 12x12 grid with obstacles and terrain costs
+	rng := rand.New(rand.NewSource(11))
+	grid := make([][]int, 12)
+	terrain := make([]float64, 144)
+	for y := range grid {
+		grid[y] = make([]int, 12)
+		for x := range grid[y] {
+			grid[y][x] = graph.NodeID(x, y, 12)
+			if rng.Float64() < 0.3 {
+				grid[y][x] = -1
+			}
+			terrain[graph.NodeID(x, y, 12)] = float64(1 + rng.Intn(4))
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		metric *graph.Metric
+	}{
+		{"BFS", nil},
+		{"Octile", &graph.Metric{Octile: true}},
+		{"Terrain", &graph.Metric{Cost: terrain}},
+		{"Octile terrain", &graph.Metric{Octile: true, Cost: terrain}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := graph.NewGraph(12, 12)
+			g.Grid = grid
+			g.Metric = tc.metric
+			g.BuildAdjlist()
+
+			for start := range g.AdjList {
+				for end := range g.AdjList {
+					expected := ShortestDistance(g, start, end)
+					if got := BidirectionalDistance(g, start, end); math.Abs(got-expected) > 1e-9 {
+						t.Fatalf("Bidirectional %d -> %d is %f, expected %f", start, end, got, expected)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBidirectionalBFS(t *testing.T) {
+	g := graph.NewGraph(3, 4)
+	g.Grid = [][]int{
+		{0, 1, 2, 3},
+		{4, -1, -1, 7},
+		{8, 9, -1, 11},
+	}
+	g.BuildAdjlist()
+	c := g.Dense()
+
+	tests := []struct {
+		start, end, expected int
+	}{
+		{0, 0, 0},
+		{0, 3, 3},
+		{9, 11, 8},
+		{0, 5, -1}, // obstacle
+		{8, 7, 6},
+	}
+	for _, tt := range tests {
+		if got := BidirectionalBFS(c, tt.start, tt.end); got != tt.expected {
+			t.Errorf("BidirectionalBFS(%d, %d) = %d, expected %d", tt.start, tt.end, got, tt.expected)
+		}
+		if got := BidirectionalDijkstra(c, tt.start, tt.end); got != float64(tt.expected) {
+			t.Errorf("BidirectionalDijkstra(%d, %d) = %f, expected %d", tt.start, tt.end, got, tt.expected)
+		}
+	}
+
+	// two separate components
+	g.Grid[0][2], g.Grid[1][3] = -1, -1
+	g.AdjList = map[int][]int{}
+	g.BuildAdjlist()
+	if got := BidirectionalBFS(g.Dense(), 0, 11); got != -1 {
+		t.Errorf("Expected -1 for unreachable node, got %d", got)
+	}
+	if got := BidirectionalDijkstra(g.Dense(), 0, 11); got != -1 {
+		t.Errorf("Expected -1 for unreachable node, got %f", got)
+	}
+}
+
+func TestBidirectionalInComponent(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+
+	for start := range g.AdjList {
+		for end := range g.AdjList {
+			component := FindSmallestConvexComponent(g, start, end)
+			if got, expected := BidirectionalDistance(component, start, end), ShortestDistance(g, start, end); math.Abs(got-expected) > 1e-9 {
+				t.Errorf("Bidirectional %d -> %d in component is %f, expected %f", start, end, got, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"math"
+)
+
+// computes the shortest distance between two nodes in their smallest component of a compact hierarchy
+// returns -1 if a node is not part of the graph or end is not reachable
+func CompactDistance(h *graph.CompactHierarchy, startID, endID int) float64 {
+	component := h.SmallestComponent(startID, endID)
+	if component == -1 {
+		return -1
+	}
+	return ComponentDistance(h, component, startID, endID)
+}
+
+// computes the shortest distance between two nodes with a search restricted to a component of a compact hierarchy,
+// bfs is used for unit costs, dijkstra otherwise
+func ComponentDistance(h *graph.CompactHierarchy, component, startID, endID int) float64 {
+	if !(h.Contains(component, startID) && h.Contains(component, endID)) {
+		return -1
+	}
+	if startID == endID {
+		return 0
+	}
+	start, _ := h.CSR.Index(startID)
+	end, _ := h.CSR.Index(endID)
+	if h.CSR.Weights != nil {
+		return componentDijkstra(h, h.Components[component], start, end)
+	}
+	return float64(componentBFS(h, h.Components[component], start, end))
+}
+
+// bfs between dense indices that only visits members of component,
+// visited nodes are tracked by their position relative to the start of the component
+func componentBFS(h *graph.CompactHierarchy, component graph.CompactComponent, start, end int) int {
+	visited := make([]bool, component.End-component.Start)
+	queue := make([]int, 0, component.End-component.Start)
+	queue = append(queue, start)
+	visited[h.Pos[start]-component.Start] = true
+	head := 0  // pointer
+	depth := 0 // depth for distance
+
+	for head < len(queue) {
+		levelSize := len(queue) - head
+		depth++
+
+		// visit every node of current level
+		for range levelSize {
+			current := queue[head]
+			head++
+
+			for _, neighbor := range h.CSR.Adjacent(current) {
+				p := h.Pos[neighbor]
+				// skip nodes outside of the component
+				if p < component.Start || p >= component.End {
+					continue
+				}
+				if neighbor == end {
+					return depth
+				}
+				if !visited[p-component.Start] {
+					visited[p-component.Start] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+
+	// return -1 if end is not reachable from start
+	return -1
+}
+
+// dijkstra between dense indices that only visits members of component
+func componentDijkstra(h *graph.CompactHierarchy, component graph.CompactComponent, start, end int) float64 {
+	cost := make([]float64, component.End-component.Start)
+	for i := range cost {
+		cost[i] = math.Inf(1)
+	}
+	settled := make([]bool, len(cost))
+	queue := graph.CostHeap{{Node: start, Cost: 0}}
+	cost[h.Pos[start]-component.Start] = 0
+
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(graph.CostItem)
+		p := h.Pos[item.Node] - component.Start
+		// skip outdated entries
+		if settled[p] {
+			continue
+		}
+		if item.Node == end {
+			return item.Cost
+		}
+		settled[p] = true
+
+		weights := h.CSR.AdjacentWeights(item.Node)
+		for k, neighbor := range h.CSR.Adjacent(item.Node) {
+			q := h.Pos[neighbor] - component.Start
+			// skip nodes outside of the component
+			if q < 0 || q >= len(cost) {
+				continue
+			}
+			newCost := item.Cost + weights[k]
+			if !settled[q] && newCost < cost[q] {
+				cost[q] = newCost
+				heap.Push(&queue, graph.CostItem{Node: neighbor, Cost: newCost})
+			}
+		}
+	}
+
+	// return -1 if end is not reachable from start
+	return -1
+}
@@ -0,0 +1,40 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"testing"
+)
+
+func TestCompactDistance(t *testing.T) {
+	testCases := []struct {
+		name   string
+		metric *graph.Metric
+	}{
+		{"Unit", nil},
+		{"Octile", &graph.Metric{Octile: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestHierarchy(t, tc.metric)
+			h := graph.NewCompactHierarchy(g)
+
+			for start := range g.AdjList {
+				for end := range g.AdjList {
+					component := FindSmallestConvexComponent(g, start, end)
+					if size := h.Size(h.SmallestComponent(start, end)); size != len(component.AdjList) {
+						t.Errorf("Component of %d -> %d has %d nodes, expected %d", start, end, size, len(component.AdjList))
+					}
+					expected := ShortestDistance(component, start, end)
+					if got := CompactDistance(h, start, end); math.Abs(got-expected) > 1e-9 {
+						t.Errorf("Distance %d -> %d is %f, expected %f", start, end, got, expected)
+					}
+				}
+			}
+			if CompactDistance(h, 0, 7) != -1 {
+				t.Errorf("Expected -1 for obstacle")
+			}
+		})
+	}
+}
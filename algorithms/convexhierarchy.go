@@ -6,32 +6,88 @@ import (
 	"bachelor-project/graph"
 	"bachelor-project/graphdecomp"
 	"context"
+	"math"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
 )
 
-type sep func(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool)
+// separator function and the name it is recorded with in the provenance of hierarchy nodes
+type namedSep struct {
+	name string
+	f    SeparatorFunc
+}
 
 // Create convex subgraphes
 func BuildConvexHierarchy(g *graph.Graph) {
+	score := loadSplitScore()
+	decomposeRoot(g, score)
+	buildSubtrees(g.Childs, score)
+}
+
+// Same as BuildConvexHierarchy, but independent subtrees are decomposed by a pool of workers (one per CPU if
+// workers <= 0). Heuristics draw their random choices from config.Seed and the node, so the tree has the same shape
+// as the sequential one unless a heuristic reaches its time limit in only one of the builds
+func BuildConvexHierarchyParallel(g *graph.Graph, workers int) {
+	BuildConvexHierarchyScored(g, workers, loadSplitScore())
+}
+
+// Same as BuildConvexHierarchyParallel, but the best mode of the pipeline picks decompositions with score
+// instead of the score set with SetSplitScore, so builds at the same time can use different scores
+func BuildConvexHierarchyScored(g *graph.Graph, workers int, score SplitScoreFunc) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if score == nil {
+		score = DefaultSplitScore
+	}
+	decomposeRoot(g, score)
+	buildSubtreesParallel(g.Childs, workers, score)
+}
+
+// split root g into its components or with the pipeline
+func decomposeRoot(g *graph.Graph, score SplitScoreFunc) {
 	// prevent undefined behavior, by decomposing graph if it already has components
+	startTime := time.Now()
 	childs, ok := graphdecomp.DecomposeInputComponents(g)
 
 	if ok {
 		g.Childs = childs
+		runTime := time.Since(startTime)
+		g.SetProvenance(&graph.Provenance{
+			Heuristic: "components",
+			Attempts:  []graph.Attempt{{Heuristic: "components", Outcome: graph.OutcomeSuccess, Time: runTime}},
+			Time:      runTime,
+			Balance:   balanceOf(g),
+		})
 	} else {
-		g.Childs = pipeline(g)
+		g.Childs = pipeline(g, score)
 	}
+	setSeparator(g)
+}
 
+// split a child, its grid and the CSR cached by the heuristics are not needed afterwards
+func decomposeNode(c *graph.Graph, score SplitScoreFunc) {
+	c.Childs = pipeline(c, score)
+	c.Grid = nil
+	c.ResetDense()
+	setSeparator(c)
+}
+
+// Decompose given graphs and all their descendants
+func buildSubtrees(childs []*graph.Graph, score SplitScoreFunc) {
 	stack := []*graph.Graph{}
-	for i := len(g.Childs) - 1; i >= 0; i-- {
-		stack = append(stack, g.Childs[i])
+	for i := len(childs) - 1; i >= 0; i-- {
+		stack = append(stack, childs[i])
 	}
 
 	// Build Tree preorder iterative
 	for len(stack) > 0 {
 		c := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
-		c.Childs = pipeline(c)
-		c.Grid = nil
+		decomposeNode(c, score)
 
 		for i := len(c.Childs) - 1; i >= 0; i-- {
 			stack = append(stack, c.Childs[i])
@@ -39,50 +95,176 @@ func BuildConvexHierarchy(g *graph.Graph) {
 	}
 }
 
-// pipeline for using several heuristics to compute convex subgraphs
-func pipeline(g *graph.Graph) []*graph.Graph {
+// Decompose given graphs and all their descendants with a pool of workers sharing one stack,
+// workers wait for new nodes as long as another worker is still decomposing
+func buildSubtreesParallel(childs []*graph.Graph, workers int, score SplitScoreFunc) {
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	stack := slices.Clone(childs)
+	slices.Reverse(stack)
+	active := 0 // workers decomposing a node
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			for {
+				for len(stack) == 0 && active > 0 {
+					cond.Wait()
+				}
+				if len(stack) == 0 {
+					return // every node is decomposed
+				}
+				c := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				active++
+				mu.Unlock()
+
+				decomposeNode(c, score)
+
+				mu.Lock()
+				for i := len(c.Childs) - 1; i >= 0; i-- {
+					stack = append(stack, c.Childs[i])
+				}
+				active--
+				cond.Broadcast()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Store nodes of g that belong to no child as separator of g
+func setSeparator(g *graph.Graph) {
+	if len(g.Childs) == 0 {
+		g.Separator = nil
+		return
+	}
+	separator := []int{}
+	for node := range g.AdjList {
+		inChild := false
+		for _, child := range g.Childs {
+			if _, exists := child.AdjList[node]; exists {
+				inChild = true
+				break
+			}
+		}
+		if !inChild {
+			separator = append(separator, node)
+		}
+	}
+	slices.Sort(separator)
+	g.Separator = separator
+}
+
+// pipeline for using several heuristics to compute convex subgraphs,
+// every heuristic tried and its outcome are recorded in the provenance of g, score is used by the best mode
+func pipeline(g *graph.Graph, score SplitScoreFunc) []*graph.Graph {
+	g.SetProvenance(nil)
 	if len(g.AdjList) < 3 {
 		return nil
 	}
-	// separators.KaFFPaSeparator,  separators.OneShortestPath, separators.TwoShortestPath, separators.RowColumn, separators.HoleCutting
-	sepFuncs := []sep{separators.OneShortestPath, separators.TwoShortestPath, separators.RowColumn, separators.HoleCutting}
-	/*
-	   pipeline:
-	   kaffpa
-	   separating shortest path
-	   two separating shortest path
-	   each row and column
-	   hole cutting
-	*/
+	// heuristics of config.Pipeline in order, see registry.go
+	sepFuncs := pipelineSeparators()
+	provenance := &graph.Provenance{Attempts: []graph.Attempt{}}
+	g.SetProvenance(provenance)
+	pipelineStart := time.Now()
+	defer func() { provenance.Time = time.Since(pipelineStart) }()
+
+	var childs []*graph.Graph
+	switch config.PipelineMode {
+	case PipelinePortfolio:
+		childs = portfolio(g, sepFuncs, provenance)
+	case PipelineBest:
+		childs = best(g, sepFuncs, provenance, score)
+	default:
+		childs = firstValid(g, sepFuncs, provenance)
+	}
+	if childs != nil {
+		provenance.Balance = largestShare(len(g.AdjList), childs)
+	}
+	return childs
+}
+
+// tries heuristics one after another, returns the first valid decomposition
+func firstValid(g *graph.Graph, sepFuncs []namedSep, provenance *graph.Provenance) []*graph.Graph {
 	// try every function (heuristic) in array
 	for _, sepFunc := range sepFuncs {
-		ctx, cancel := context.WithTimeout(context.Background(), config.Time)
+		ctx, cancel := heuristicContext(context.Background(), g, sepFunc.name)
+		startTime := time.Now()
 
 		resultChan := make(chan []*graph.Graph, 1) // channel for result
-		go func(f sep) {
+		go func(f SeparatorFunc) {
 			graphs, ok := f(g, ctx)
 			if ok {
 				resultChan <- graphs
 			} else {
 				resultChan <- nil
 			}
-		}(sepFunc)
+		}(sepFunc.f)
 
+		attempt := graph.Attempt{Heuristic: sepFunc.name, Outcome: graph.OutcomeRejected}
 		select {
 		case res := <-resultChan:
 			cancel()
+			attempt.Time = time.Since(startTime)
 			// return only positive result
 			if res != nil {
+				attempt.Outcome = graph.OutcomeSuccess
+				provenance.Attempts = append(provenance.Attempts, attempt)
+				provenance.Heuristic = sepFunc.name
 				return res
 			} // else: try another heuristic
 		case <-ctx.Done():
 			cancel()
+			attempt.Time = time.Since(startTime)
+			attempt.Outcome = graph.OutcomeTimeout
 		}
+		provenance.Attempts = append(provenance.Attempts, attempt)
 	}
 	// no heuristic found a valid alpha balanced convex decomposition
 	return nil
 }
 
+// context of one heuristic on g: its time limit and a random source seeded for g
+func heuristicContext(parent context.Context, g *graph.Graph, name string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, heuristicTime(name))
+	return separators.WithRand(ctx, rand.New(rand.NewSource(nodeSeed(g)))), cancel
+}
+
+// seed of the random choices of heuristics on g, depends on config.Seed and the smallest nodeid of g only
+func nodeSeed(g *graph.Graph) int64 {
+	seed := uint64(config.Seed)
+	if len(g.AdjList) > 0 {
+		// no CSR just for the smallest nodeid, it would stay cached on g
+		smallest := math.MaxInt
+		for node := range g.AdjList {
+			smallest = min(smallest, node)
+		}
+		seed ^= uint64(smallest+1) * 0x9e3779b97f4a7c15
+	}
+	return int64(seed)
+}
+
+// Returns nodes of the largest child / nodes of g, 0 if g is not split
+func balanceOf(g *graph.Graph) float64 {
+	return largestShare(len(g.AdjList), g.Childs)
+}
+
+func largestShare(nodes int, childs []*graph.Graph) float64 {
+	largest := 0
+	for _, child := range childs {
+		largest = max(largest, len(child.AdjList))
+	}
+	if nodes == 0 {
+		return 0
+	}
+	return float64(largest) / float64(nodes)
+}
+
 // Returns smallest convex component that has start and end -node in a single adjacency list
 func FindSmallestConvexComponent(g *graph.Graph, startNode, endNode int) *graph.Graph {
 	// Check if key (node) exists
@@ -104,3 +286,21 @@ func FindSmallestConvexComponent(g *graph.Graph, startNode, endNode int) *graph.
 
 	return g
 }
+
+// Returns true if both hierarchies have the same tree: same nodes in every component and childs in the same order
+func SameShape(a, b *graph.Graph) bool {
+	if len(a.AdjList) != len(b.AdjList) || len(a.Childs) != len(b.Childs) {
+		return false
+	}
+	for node := range a.AdjList {
+		if _, exists := b.AdjList[node]; !exists {
+			return false
+		}
+	}
+	for i := range a.Childs {
+		if !SameShape(a.Childs[i], b.Childs[i]) {
+			return false
+		}
+	}
+	return true
+}
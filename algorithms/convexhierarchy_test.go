@@ -4,6 +4,8 @@ import (
 	"bachelor-project/config"
 	"bachelor-project/graph"
 	"fmt"
+	"math"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -28,6 +30,7 @@ func TestBuildConvexHierarchy(t *testing.T) {
 	if g.Childs == nil {
 		t.Error("Expected non-nil Childs after hierarchy build")
 	}
+	checkSeparators(t, g)
 	// PrintHierarchyAdjLists(g, 0)
 	// PrintHierarchyGrids(g, 0)
 }
@@ -112,3 +115,207 @@ func TestFindSmallestConvexComponent(t *testing.T) {
 		t.Error("Expected nil for non-existent end node")
 	}
 }
+
+func TestBuildConvexHierarchyWeighted(t *testing.T) {
+	terrain := testTerrain()
+
+	testCases := []struct {
+		name   string
+		metric *graph.Metric
+	}{
+		{"Octile", &graph.Metric{Octile: true}},
+		{"Terrain", &graph.Metric{Cost: terrain}},
+		{"Octile terrain", &graph.Metric{Octile: true, Cost: terrain}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestHierarchy(t, tc.metric)
+
+			if g.Childs == nil {
+				t.Fatal("Expected non-nil Childs after hierarchy build")
+			}
+			// distances in the smallest convex component must be exact
+			for start := range g.AdjList {
+				for end := range g.AdjList {
+					component := FindSmallestConvexComponent(g, start, end)
+					expected := ShortestDistance(g, start, end)
+					if got := ShortestDistance(component, start, end); math.Abs(got-expected) > 1e-9 {
+						t.Errorf("Distance %d -> %d in component is %f, expected %f", start, end, got, expected)
+					}
+				}
+			}
+		})
+	}
+}
+
+// every split stores exactly the nodes that are in no child as separator
+func checkSeparators(t *testing.T, g *graph.Graph) {
+	t.Helper()
+	if len(g.Childs) == 0 {
+		if g.Separator != nil {
+			t.Errorf("Expected no separator for leaf, got %v", g.Separator)
+		}
+		return
+	}
+	count := len(g.Separator)
+	for _, child := range g.Childs {
+		count += len(child.AdjList)
+		for _, node := range g.Separator {
+			if _, exists := child.AdjList[node]; exists {
+				t.Errorf("Separator node %d is part of a child", node)
+			}
+		}
+		checkSeparators(t, child)
+	}
+	if count != len(g.AdjList) {
+		t.Errorf("Expected separator and childs to cover %d nodes, got %d", len(g.AdjList), count)
+	}
+}
+
+// every hierarchy node with at least 3 nodes records the heuristics the pipeline tried
+func checkProvenance(t *testing.T, g *graph.Graph) {
+	t.Helper()
+	p := g.Provenance()
+	if len(g.AdjList) < 3 && len(g.Childs) == 0 {
+		if p != nil {
+			t.Errorf("Expected no provenance for %d nodes, got %+v", len(g.AdjList), p)
+		}
+		return
+	}
+	if p == nil {
+		t.Errorf("Expected provenance for %d nodes", len(g.AdjList))
+		return
+	}
+	for i, attempt := range p.Attempts {
+		success := attempt.Outcome == graph.OutcomeSuccess
+		if success != (i == len(p.Attempts)-1 && len(g.Childs) > 0) {
+			t.Errorf("Unexpected outcome %v of attempt %d (%d attempts, %d childs)", attempt.Outcome, i, len(p.Attempts), len(g.Childs))
+		}
+		if attempt.Time > p.Time {
+			t.Errorf("Attempt %s took %v, longer than the pipeline %v", attempt.Heuristic, attempt.Time, p.Time)
+		}
+	}
+	if len(g.Childs) == 0 {
+		if p.Heuristic != "" || p.Balance != 0 {
+			t.Errorf("Expected no heuristic and balance for leaf, got %+v", p)
+		}
+		return
+	}
+	if p.Heuristic != p.Attempts[len(p.Attempts)-1].Heuristic {
+		t.Errorf("Expected heuristic %q of the successful attempt, got %q", p.Attempts[len(p.Attempts)-1].Heuristic, p.Heuristic)
+	}
+	if p.Balance != balanceOf(g) || p.Balance <= 0 || p.Balance > config.Alpha && p.Heuristic != "components" {
+		t.Errorf("Unexpected balance %g", p.Balance)
+	}
+	for _, child := range g.Childs {
+		checkProvenance(t, child)
+	}
+}
+
+func TestPipelineProvenance(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+	checkProvenance(t, g)
+	if g.Provenance() == nil || g.Provenance().Heuristic == "" {
+		t.Errorf("Expected root split by a heuristic, got %+v", g.Provenance())
+	}
+
+	// graph with two components is split without heuristics
+	components := graph.NewGraph(1, 5)
+	components.Grid = [][]int{{0, 1, 2, -1, 4}}
+	components.BuildAdjlist()
+	BuildConvexHierarchy(components)
+	checkProvenance(t, components)
+	if p := components.Provenance(); p == nil || p.Heuristic != "components" || p.Balance != 0.75 {
+		t.Errorf("Expected split into components with balance 0.75, got %+v", p)
+	}
+}
+
+func TestBuildConvexHierarchyParallel(t *testing.T) {
+	// 10x10 map with scattered obstacles and a wall
+	grid := make([][]int, 10)
+	for y := range grid {
+		grid[y] = make([]int, 10)
+		for x := range grid[y] {
+			grid[y][x] = y*10 + x
+			if (x*7+y*11)%13 == 0 || (x == 5 && y > 2 && y < 7) {
+				grid[y][x] = -1
+			}
+		}
+	}
+	build := func(metric *graph.Metric, workers int) *graph.Graph {
+		g := graph.NewGraph(10, 10)
+		g.Grid = grid
+		g.Metric = metric
+		g.BuildAdjlist()
+		if workers == 0 {
+			BuildConvexHierarchy(g)
+		} else {
+			BuildConvexHierarchyParallel(g, workers)
+		}
+		return g
+	}
+
+	for _, metric := range []*graph.Metric{nil, {Octile: true}} {
+		sequential := build(metric, 0)
+		if countNodes(sequential) < 5 {
+			t.Fatalf("Expected a deep hierarchy, got %d nodes", countNodes(sequential))
+		}
+		if !SameShape(sequential, build(metric, 0)) {
+			t.Errorf("Expected same shape of two sequential builds (octile %v)", metric != nil)
+		}
+		for _, workers := range []int{1, 4} {
+			parallel := build(metric, workers)
+			if !SameShape(sequential, parallel) {
+				t.Errorf("Expected same shape with %d workers as sequential (octile %v)", workers, metric != nil)
+			}
+			checkSeparators(t, parallel)
+			checkProvenance(t, parallel)
+		}
+	}
+
+	// another seed gives heuristics other random sources (see TestShuffleWithRand),
+	// sequential and parallel builds still have the same shape
+	defer func(seed int64) { config.Seed = seed }(config.Seed)
+	g := build(nil, 0)
+	seed := nodeSeed(g)
+	config.Seed = 7
+	if nodeSeed(g) == seed {
+		t.Errorf("Expected seed of the root to depend on config.Seed")
+	}
+	if !SameShape(build(nil, 0), build(nil, 3)) {
+		t.Errorf("Expected same shape for seed 7")
+	}
+}
+
+func TestSavedHierarchy(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+
+	path := filepath.Join(t.TempDir(), "hierarchy.bin")
+	if err := graph.SaveHierarchy(path, g, graph.BuildParams{Alpha: config.Alpha, Timeout: config.Time}); err != nil {
+		t.Fatalf("Unexpected save error: %v", err)
+	}
+	loaded, params, err := graph.LoadHierarchy(path)
+	if err != nil {
+		t.Fatalf("Unexpected load error: %v", err)
+	}
+	if params.Alpha != config.Alpha {
+		t.Errorf("Expected alpha %f, got %f", config.Alpha, params.Alpha)
+	}
+	checkSeparators(t, loaded)
+	checkProvenance(t, loaded)
+
+	// loaded hierarchy answers queries with the same components
+	for start := range g.AdjList {
+		for end := range g.AdjList {
+			expected := FindSmallestConvexComponent(g, start, end)
+			got := FindSmallestConvexComponent(loaded, start, end)
+			if len(expected.AdjList) != len(got.AdjList) {
+				t.Errorf("Component of %d -> %d has %d nodes, expected %d", start, end, len(got.AdjList), len(expected.AdjList))
+			}
+			if d1, d2 := ShortestDistance(g, start, end), ShortestDistance(got, start, end); math.Abs(d1-d2) > 1e-9 {
+				t.Errorf("Distance %d -> %d is %f, expected %f", start, end, d2, d1)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"math"
+)
+
+// computes the cost of a shortest path between two nodes with dijkstra on the compact (CSR) form of a graph
+// edges without weights cost 1, returns -1 if endID is not reachable from startID
+func Dijkstra(c *graph.CSR, startID, endID int) float64 {
+	// base case: start and end are the same node
+	if startID == endID {
+		return 0
+	}
+
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return -1
+	}
+
+	cost := make([]float64, c.Len())
+	for i := range cost {
+		cost[i] = math.Inf(1)
+	}
+	settled := make([]bool, c.Len())
+	queue := graph.CostHeap{{Node: start, Cost: 0}}
+	cost[start] = 0
+
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(graph.CostItem)
+		// skip outdated entries
+		if settled[item.Node] {
+			continue
+		}
+		if item.Node == end {
+			return item.Cost
+		}
+		settled[item.Node] = true
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			newCost := item.Cost + 1
+			if weights != nil {
+				newCost = item.Cost + weights[k]
+			}
+			if !settled[neighbor] && newCost < cost[neighbor] {
+				cost[neighbor] = newCost
+				heap.Push(&queue, graph.CostItem{Node: neighbor, Cost: newCost})
+			}
+		}
+	}
+
+	// return -1 if endID is not reachable from startID
+	return -1
+}
+
+// computes the cost of a shortest path between two nodes of an adjacency list with dijkstra,
+// counterpart of BreadthFirstSearch for edge costs (e.g. g.EdgeCost)
+func DijkstraSearch(adjlist map[int][]int, cost func(v, w int) float64, startID, endID int) float64 {
+	return Dijkstra(graph.NewWeightedCSR(adjlist, cost), startID, endID)
+}
+
+// computes the shortest distance between two nodes of a graph or hierarchy component
+// bfs is used for unit costs, dijkstra otherwise, -1 if there is no component (g is nil)
+func ShortestDistance(g *graph.Graph, startID, endID int) float64 {
+	if g == nil {
+		return -1
+	}
+	if g.Weighted() {
+		return Dijkstra(g.Dense(), startID, endID)
+	}
+	return float64(BreadthFirstSearchCSR(g.Dense(), startID, endID))
+}
@@ -0,0 +1,52 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"testing"
+)
+
+func TestDijkstra(t *testing.T) {
+	/*
+		0  1  2
+		3  @  5
+		6  7  8
+	*/
+	g := graph.NewGraph(3, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, -1, 5},
+		{6, 7, 8},
+	}
+	g.Metric = &graph.Metric{Octile: true}
+	g.BuildAdjlist()
+	c := g.Dense()
+
+	if cost := Dijkstra(c, 0, 0); cost != 0 {
+		t.Errorf("Cost from node 0 to itself should be 0, got %f", cost)
+	}
+	// no corner cutting around the obstacle: 0 -> 1 -> 2 -> 5 -> 8
+	if cost := Dijkstra(c, 0, 8); cost != 4 {
+		t.Errorf("Cost from node 0 to node 8 should be 4, got %f", cost)
+	}
+	if cost := Dijkstra(c, 0, 99); cost != -1 {
+		t.Errorf("Cost to unknown node should be -1, got %f", cost)
+	}
+
+	// open 3x3 grid: one diagonal and one straight step
+	g.Grid[1][1] = 4
+	g.AdjList = map[int][]int{}
+	g.BuildAdjlist()
+	expected := 1 + math.Sqrt2
+	if cost := ShortestDistance(g, 0, 7); math.Abs(cost-expected) > 1e-9 {
+		t.Errorf("Cost from node 0 to node 7 should be %f, got %f", expected, cost)
+	}
+
+	// without weights dijkstra equals bfs
+	g.Metric = nil
+	g.AdjList = map[int][]int{}
+	g.BuildAdjlist()
+	if cost := Dijkstra(g.Dense(), 0, 8); cost != 4 {
+		t.Errorf("Unit cost from node 0 to node 8 should be 4, got %f", cost)
+	}
+}
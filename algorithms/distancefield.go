@@ -0,0 +1,96 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+)
+
+// computes the distances from start to every node of a graph or hierarchy component,
+// only nodes with a distance of at most radius are returned (every reachable node if radius is negative)
+func DistanceField(g *graph.Graph, startID int, radius float64) map[int]float64 {
+	field := map[int]float64{}
+	if g == nil {
+		return field
+	}
+	c := g.Dense()
+	start, exists := c.Index(startID)
+	if !exists {
+		return field
+	}
+	if radius < 0 {
+		radius = math.Inf(1)
+	}
+
+	settle := func(i int, cost float64) bool {
+		if cost > radius {
+			return false
+		}
+		field[c.NodeIDs[i]] = cost
+		return true
+	}
+
+	if g.Weighted() {
+		multiSourceDijkstra(c, []int{start}, []float64{0}, nil, settle)
+		return field
+	}
+
+	// bfs level by level
+	depth := make([]int, c.Len())
+	for i := range depth {
+		depth[i] = -1
+	}
+	depth[start] = 0
+	queue := []int{start}
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		if !settle(current, float64(depth[current])) {
+			break
+		}
+		for _, neighbor := range c.Adjacent(current) {
+			if depth[neighbor] == -1 {
+				depth[neighbor] = depth[current] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return field
+}
+
+// Returns all nodes with a distance of at most radius from start and their distances.
+// The search starts in the smallest convex component of start and only moves to the parent component if a boundary
+// node of the component (a node with a neighbor outside of it) is in range, every component beyond is skipped
+func Isochrone(g *graph.Graph, startID int, radius float64) map[int]float64 {
+	if _, exists := g.AdjList[startID]; !exists {
+		return map[int]float64{}
+	}
+
+	// components containing start from the root downwards
+	path := []*graph.Graph{g}
+	for {
+		child := childOf(path[len(path)-1], startID)
+		if child == nil {
+			break
+		}
+		path = append(path, child)
+	}
+
+	for level := len(path) - 1; level > 0; level-- {
+		component := path[level]
+		field := DistanceField(component, startID, radius)
+		if !boundaryInRange(g, component, field) {
+			// distances in a convex component are distances in g, the range can't leave the component
+			return field
+		}
+	}
+	return DistanceField(g, startID, radius)
+}
+
+// Returns true if a node of the field has a neighbor in root g that is not part of the component
+func boundaryInRange(g, component *graph.Graph, field map[int]float64) bool {
+	for node := range field {
+		if len(component.AdjList[node]) < len(g.AdjList[node]) {
+			return true
+		}
+	}
+	return false
+}
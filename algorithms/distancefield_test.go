@@ -0,0 +1,67 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDistanceField(t *testing.T) {
+	g := graph.NewGraph(2, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, -1, 5},
+	}
+	g.BuildAdjlist()
+
+	tests := []struct {
+		radius   float64
+		expected map[int]float64
+	}{
+		{-1, map[int]float64{0: 0, 1: 1, 2: 2, 3: 1, 5: 3}},
+		{1, map[int]float64{0: 0, 1: 1, 3: 1}},
+		{0, map[int]float64{0: 0}},
+	}
+	for _, tt := range tests {
+		if field := DistanceField(g, 0, tt.radius); !reflect.DeepEqual(field, tt.expected) {
+			t.Errorf("Radius %f: expected %v, got %v", tt.radius, tt.expected, field)
+		}
+	}
+
+	g.Metric = &graph.Metric{Cost: []float64{1, 3, 1, 1, 0, 1}}
+	g.ResetDense()
+	expected := map[int]float64{0: 0, 1: 2, 3: 1}
+	if field := DistanceField(g, 0, 2.5); !reflect.DeepEqual(field, expected) {
+		t.Errorf("Weighted: expected %v, got %v", expected, field)
+	}
+	if field := DistanceField(g, 4, -1); len(field) != 0 {
+		t.Errorf("Expected empty field for obstacle, got %v", field)
+	}
+}
+
+func TestIsochrone(t *testing.T) {
+	for _, metric := range []*graph.Metric{{Octile: true}, {Octile: true, Cost: make([]float64, 36)}} {
+		if metric.Cost != nil {
+			for i := range metric.Cost {
+				metric.Cost[i] = float64(1 + i%3)
+			}
+		}
+		g := newTestHierarchy(t, metric)
+
+		for start := range g.AdjList {
+			for _, radius := range []float64{0, 1, 1.5, 2, 3, 5, 100} {
+				expected := DistanceField(g, start, radius)
+				got := Isochrone(g, start, radius)
+				if len(got) != len(expected) {
+					t.Fatalf("Isochrone of %d with radius %f has %d nodes, expected %d", start, radius, len(got), len(expected))
+				}
+				for node, distance := range expected {
+					if math.Abs(got[node]-distance) > 1e-9 {
+						t.Errorf("Isochrone of %d: distance to %d is %f, expected %f", start, node, got[node], distance)
+					}
+				}
+			}
+		}
+	}
+}
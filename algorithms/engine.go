@@ -0,0 +1,182 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"runtime"
+	"sync"
+)
+
+// Distance query between two nodes of the root graph
+type Query struct {
+	Start int
+	End   int
+}
+
+// Answers batches of queries on a built hierarchy with a pool of workers.
+// The hierarchy must not be modified while the engine is used
+type QueryEngine struct {
+	root    *graph.Graph
+	labels  *HierarchyLabels // component lookup
+	workers int
+	buffers sync.Pool // *searchBuffer, one is held by every running worker
+}
+
+// number of queries a worker takes at once
+const queryChunk = 64
+
+// Create engine for the hierarchy of root g, workers <= 0 uses one worker per CPU
+func NewQueryEngine(g *graph.Graph, workers int) *QueryEngine {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	size := g.Dense().Len()
+	e := &QueryEngine{root: g, labels: NewHierarchyLabels(g), workers: workers}
+	e.buffers.New = func() any { return newSearchBuffer(size) }
+	return e
+}
+
+// Number of workers
+func (e *QueryEngine) Workers() int {
+	return e.workers
+}
+
+// Answers queries in their smallest convex component, result i is the distance of queries[i] (-1 if not reachable)
+func (e *QueryEngine) Distances(queries []Query) []float64 {
+	results := make([]float64, len(queries))
+	chunks := make(chan int, (len(queries)+queryChunk-1)/queryChunk)
+	for first := 0; first < len(queries); first += queryChunk {
+		chunks <- first
+	}
+	close(chunks)
+
+	var wg sync.WaitGroup
+	for range min(e.workers, cap(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := e.buffers.Get().(*searchBuffer)
+			defer e.buffers.Put(buf)
+
+			for first := range chunks {
+				for i := first; i < min(first+queryChunk, len(queries)); i++ {
+					component := e.labels.SmallestComponent(queries[i].Start, queries[i].End)
+					results[i] = buf.distance(component, queries[i].Start, queries[i].End)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Scratch memory of a worker, sized for the root graph so it fits every component.
+// Entries are valid if their stamp equals the current stamp, so nothing has to be cleared between searches
+type searchBuffer struct {
+	stamp   uint32
+	visited []uint32 // stamp of dense index if reached
+	settled []uint32 // stamp of dense index if settled (dijkstra)
+	depth   []int
+	cost    []float64
+	queue   []int
+	heap    graph.CostHeap
+}
+
+func newSearchBuffer(size int) *searchBuffer {
+	return &searchBuffer{
+		visited: make([]uint32, size),
+		settled: make([]uint32, size),
+		depth:   make([]int, size),
+		cost:    make([]float64, size),
+		queue:   make([]int, 0, size),
+	}
+}
+
+// start a new search, invalidates all entries
+func (b *searchBuffer) next() {
+	b.stamp++
+	if b.stamp == 0 { // overflow, stamps of old searches could become valid again
+		clear(b.visited)
+		clear(b.settled)
+		b.stamp = 1
+	}
+	b.queue = b.queue[:0]
+	b.heap = b.heap[:0]
+}
+
+// shortest distance in a graph or component with bfs for unit costs and dijkstra otherwise
+func (b *searchBuffer) distance(g *graph.Graph, startID, endID int) float64 {
+	if g == nil {
+		return -1
+	}
+	c := g.Dense()
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return -1
+	}
+	if start == end {
+		return 0
+	}
+	b.next()
+	if g.Weighted() {
+		return b.dijkstra(c, start, end)
+	}
+	return float64(b.bfs(c, start, end))
+}
+
+func (b *searchBuffer) bfs(c *graph.CSR, start, end int) int {
+	b.visited[start] = b.stamp
+	b.depth[start] = 0
+	b.queue = append(b.queue, start)
+	for head := 0; head < len(b.queue); head++ {
+		current := b.queue[head]
+		for _, neighbor := range c.Adjacent(current) {
+			if b.visited[neighbor] == b.stamp {
+				continue
+			}
+			if neighbor == end {
+				return b.depth[current] + 1
+			}
+			b.visited[neighbor] = b.stamp
+			b.depth[neighbor] = b.depth[current] + 1
+			b.queue = append(b.queue, neighbor)
+		}
+	}
+	return -1
+}
+
+func (b *searchBuffer) dijkstra(c *graph.CSR, start, end int) float64 {
+	b.visited[start] = b.stamp
+	b.cost[start] = 0
+	heap.Push(&b.heap, graph.CostItem{Node: start, Cost: 0})
+	for b.heap.Len() > 0 {
+		item := heap.Pop(&b.heap).(graph.CostItem)
+		// skip outdated entries
+		if b.settled[item.Node] == b.stamp {
+			continue
+		}
+		if item.Node == end {
+			return item.Cost
+		}
+		b.settled[item.Node] = b.stamp
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			newCost := item.Cost + 1
+			if weights != nil {
+				newCost = item.Cost + weights[k]
+			}
+			if b.settled[neighbor] == b.stamp {
+				continue
+			}
+			// unvisited nodes have infinite cost
+			if b.visited[neighbor] != b.stamp || newCost < b.cost[neighbor] {
+				b.visited[neighbor] = b.stamp
+				b.cost[neighbor] = newCost
+				heap.Push(&b.heap, graph.CostItem{Node: neighbor, Cost: newCost})
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,83 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQueryEngine(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	grid := make([][]int, 10)
+	terrain := make([]float64, 100)
+	for y := range grid {
+		grid[y] = make([]int, 10)
+		for x := range grid[y] {
+			grid[y][x] = graph.NodeID(x, y, 10)
+			if rng.Float64() < 0.2 {
+				grid[y][x] = -1
+			}
+			terrain[graph.NodeID(x, y, 10)] = float64(1 + rng.Intn(3))
+		}
+	}
+
+	for _, metric := range []*graph.Metric{nil, {Octile: true, Cost: terrain}} {
+		g := graph.NewGraph(10, 10)
+		g.Grid = grid
+		g.Metric = metric
+		g.BuildAdjlist()
+		BuildConvexHierarchy(g)
+
+		queries := []Query{{0, 0}, {0, 999}}
+		for range 500 {
+			queries = append(queries, Query{rng.Intn(100), rng.Intn(100)})
+		}
+
+		for _, workers := range []int{1, 3, 0} {
+			e := NewQueryEngine(g, workers)
+			// second batch reuses the pooled buffers
+			for range 2 {
+				results := e.Distances(queries)
+				if len(results) != len(queries) {
+					t.Fatalf("Expected %d results, got %d", len(queries), len(results))
+				}
+				for i, q := range queries {
+					expected := ShortestDistance(FindSmallestConvexComponent(g, q.Start, q.End), q.Start, q.End)
+					if math.Abs(results[i]-expected) > 1e-9 {
+						t.Fatalf("%d workers: query %d -> %d is %f, expected %f", e.Workers(), q.Start, q.End, results[i], expected)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestQueryEngineUnsplitRoot(t *testing.T) {
+	// too small to split, every query is answered in the root
+	g := graph.NewGraph(1, 2)
+	g.Grid = [][]int{{0, 1}}
+	g.BuildAdjlist()
+	BuildConvexHierarchy(g)
+
+	results := NewQueryEngine(g, 2).Distances([]Query{{0, 1}, {1, 1}, {0, 2}})
+	for i, expected := range []float64{1, 0, -1} {
+		if results[i] != expected {
+			t.Errorf("Expected distance %g for query %d, got %g", expected, i, results[i])
+		}
+	}
+}
+
+func TestSearchBufferStampOverflow(t *testing.T) {
+	g := graph.NewGraph(1, 3)
+	g.Grid = [][]int{{0, 1, 2}}
+	g.BuildAdjlist()
+
+	b := newSearchBuffer(3)
+	b.stamp = math.MaxUint32 - 1
+	for range 3 {
+		if d := b.distance(g, 0, 2); d != 2 {
+			t.Errorf("Expected distance 2, got %f", d)
+		}
+	}
+}
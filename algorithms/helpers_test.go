@@ -0,0 +1,39 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"testing"
+)
+
+// 6x6 map with four obstacles shared by the hierarchy tests
+func testGrid() [][]int {
+	return [][]int{
+		{0, 1, 2, 3, 4, 5},
+		{6, -1, 8, 9, -1, 11},
+		{12, 13, 14, 15, 16, 17},
+		{18, 19, -1, 21, 22, 23},
+		{24, 25, 26, 27, -1, 29},
+		{30, 31, 32, 33, 34, 35},
+	}
+}
+
+// swamp cells in the middle of the test grid
+func testTerrain() []float64 {
+	terrain := make([]float64, 36)
+	for i := range terrain {
+		terrain[i] = 1
+	}
+	terrain[14], terrain[15], terrain[21] = 4, 4, 2
+	return terrain
+}
+
+// convex hierarchy of the test grid, nil metric for unit costs
+func newTestHierarchy(t *testing.T, metric *graph.Metric) *graph.Graph {
+	t.Helper()
+	g := graph.NewGraph(6, 6)
+	g.Grid = testGrid()
+	g.Metric = metric
+	g.BuildAdjlist()
+	BuildConvexHierarchy(g)
+	return g
+}
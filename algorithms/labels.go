@@ -0,0 +1,106 @@
+package algorithms
+
+import "bachelor-project/graph"
+
+// Precomputed label of every node of a built hierarchy: the child indices on the way from the root to the deepest
+// component containing the node. The smallest common component of two nodes is reached by following the common
+// prefix of their labels, without any adjacency list lookups. Labels have to be rebuilt after the hierarchy changed
+type HierarchyLabels struct {
+	root  *graph.Graph
+	start []int32 // nodeid -> start of its label in data, label of id is data[start[id]:start[id+1]]
+	exist []bool  // nodeid -> node is part of the root graph
+	data  []int32
+}
+
+// Compute labels of all nodes of the hierarchy of root g
+func NewHierarchyLabels(g *graph.Graph) *HierarchyLabels {
+	c := g.Dense()
+	size := 0
+	if c.Len() > 0 {
+		size = c.NodeIDs[c.Len()-1] + 1
+	}
+	labels := make([][]int32, size)
+
+	// preorder walk, nodes get the path of the deepest component they are part of
+	var walk func(component *graph.Graph, path []int32)
+	walk = func(component *graph.Graph, path []int32) {
+		if len(component.Childs) == 0 {
+			for node := range component.AdjList {
+				labels[node] = path
+			}
+			return
+		}
+		for _, node := range separatorOf(component) {
+			labels[node] = path
+		}
+		for i, child := range component.Childs {
+			walk(child, append(path[:len(path):len(path)], int32(i)))
+		}
+	}
+	walk(g, []int32{})
+
+	// data is never nil, so labels of nodes in an unsplit root are empty but not nil
+	l := &HierarchyLabels{root: g, start: make([]int32, size+1), exist: make([]bool, size), data: []int32{}}
+	for _, id := range c.NodeIDs {
+		l.exist[id] = true
+	}
+	for id, label := range labels {
+		l.start[id] = int32(len(l.data))
+		l.data = append(l.data, label...)
+	}
+	l.start[size] = int32(len(l.data))
+	return l
+}
+
+// Returns label of a node (empty for nodes of the root separator or an unsplit root), nil if the node is not part of the root graph
+func (l *HierarchyLabels) Label(id int) []int32 {
+	if id < 0 || id >= len(l.exist) || !l.exist[id] {
+		return nil
+	}
+	return l.data[l.start[id]:l.start[id+1]]
+}
+
+// Returns deepest component that contains the node, nil if the node is not part of the root graph
+func (l *HierarchyLabels) Leaf(id int) *graph.Graph {
+	label := l.Label(id)
+	if label == nil {
+		return nil
+	}
+	return l.follow(label)
+}
+
+// Returns smallest convex component that contains start and end node, same result as FindSmallestConvexComponent
+func (l *HierarchyLabels) SmallestComponent(startID, endID int) *graph.Graph {
+	a, b := l.Label(startID), l.Label(endID)
+	if a == nil || b == nil {
+		return nil
+	}
+	common := 0
+	for common < min(len(a), len(b)) && a[common] == b[common] {
+		common++
+	}
+	return l.follow(a[:common])
+}
+
+// follow child indices from the root
+func (l *HierarchyLabels) follow(path []int32) *graph.Graph {
+	g := l.root
+	for _, i := range path {
+		g = g.Childs[i]
+	}
+	return g
+}
+
+// Returns stored separator of a split component, computes it for hierarchies built without separators
+func separatorOf(g *graph.Graph) []int {
+	if g.Separator != nil || len(g.Childs) == 0 {
+		return g.Separator
+	}
+	separator := []int{}
+	for node := range g.AdjList {
+		if childOf(g, node) == nil {
+			separator = append(separator, node)
+		}
+	}
+	return separator
+}
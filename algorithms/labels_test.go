@@ -0,0 +1,61 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"testing"
+)
+
+func TestHierarchyLabels(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+	l := NewHierarchyLabels(g)
+
+	for start := range g.AdjList {
+		// deepest component has no child with the node
+		leaf := l.Leaf(start)
+		if _, exists := leaf.AdjList[start]; !exists || childOf(leaf, start) != nil {
+			t.Errorf("Leaf of %d with label %v is not its deepest component", start, l.Label(start))
+		}
+		for end := range g.AdjList {
+			if got, expected := l.SmallestComponent(start, end), FindSmallestConvexComponent(g, start, end); got != expected {
+				t.Errorf("Smallest component of %d -> %d differs from FindSmallestConvexComponent", start, end)
+			}
+		}
+	}
+
+	if l.Label(7) != nil || l.Label(-1) != nil || l.Label(99) != nil || l.SmallestComponent(0, 7) != nil {
+		t.Errorf("Expected no label for nodes outside of the graph")
+	}
+	// every child index of a label leads to a component that contains the node
+	for node := range g.AdjList {
+		component := g
+		for _, i := range l.Label(node) {
+			component = component.Childs[i]
+			if _, exists := component.AdjList[node]; !exists {
+				t.Errorf("Label %v of %d leads through a component without the node", l.Label(node), node)
+				break
+			}
+		}
+	}
+	if len(l.Label(0)) == 0 {
+		t.Errorf("Expected non-empty label for node 0, root separator is %v", g.Separator)
+	}
+}
+
+func TestHierarchyLabelsUnsplitRoot(t *testing.T) {
+	// too small to split, the root has no childs
+	g := graph.NewGraph(1, 2)
+	g.Grid = [][]int{{0, 1}}
+	g.BuildAdjlist()
+	BuildConvexHierarchy(g)
+	l := NewHierarchyLabels(g)
+
+	if label := l.Label(0); label == nil || len(label) != 0 {
+		t.Errorf("Expected empty label for node 0, got %v", label)
+	}
+	if l.Leaf(1) != g || l.SmallestComponent(0, 1) != g {
+		t.Errorf("Expected root as leaf and smallest component")
+	}
+	if l.Label(2) != nil || l.SmallestComponent(0, 2) != nil {
+		t.Errorf("Expected no label for nodes outside of the graph")
+	}
+}
@@ -0,0 +1,138 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// Target node and its distance from the start node
+type TargetDistance struct {
+	Target   int
+	Distance float64
+}
+
+// Returns the target closest to start, the path from start to it and its distance.
+// One search is started from all targets at once and stops when it reaches start, it is confined to the smallest convex
+// component that contains start and all targets. Targets that are not part of g are ignored.
+// Returns -1, nil, -1 if no target is reachable
+func NearestTarget(g *graph.Graph, startID int, targetIDs []int) (int, []int, float64) {
+	targetIDs = existingNodes(g, targetIDs)
+	component := smallestComponentOf(g, append([]int{startID}, targetIDs...))
+	if component == nil || len(targetIDs) == 0 {
+		return -1, nil, -1
+	}
+	c := component.Dense()
+	start, _ := c.Index(startID)
+
+	cost := make([]float64, c.Len())
+	next := make([]int, c.Len()) // next node on the way to the nearest target, targets point to themselves
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		next[i] = -1
+	}
+	settled := make([]bool, c.Len())
+	queue := graph.CostHeap{}
+	for _, id := range targetIDs {
+		i, _ := c.Index(id)
+		cost[i] = 0
+		next[i] = i
+		heap.Push(&queue, graph.CostItem{Node: i, Cost: 0})
+	}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(graph.CostItem)
+		// skip outdated entries
+		if settled[item.Node] {
+			continue
+		}
+		if item.Node == start {
+			// follow next to the target
+			path := []int{startID}
+			for current := start; next[current] != current; {
+				current = next[current]
+				path = append(path, c.NodeIDs[current])
+			}
+			return path[len(path)-1], path, item.Cost
+		}
+		settled[item.Node] = true
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			newCost := item.Cost + 1
+			if weights != nil {
+				newCost = item.Cost + weights[k]
+			}
+			if !settled[neighbor] && newCost < cost[neighbor] {
+				cost[neighbor] = newCost
+				next[neighbor] = item.Node
+				heap.Push(&queue, graph.CostItem{Node: neighbor, Cost: newCost})
+			}
+		}
+	}
+
+	// no target is reachable from start
+	return -1, nil, -1
+}
+
+// Returns up to k reachable targets closest to start in order of distance.
+// One search from start stops when k targets are reached, it is confined to the smallest convex component
+// that contains start and all targets. Targets that are not part of g are ignored
+func KNearestTargets(g *graph.Graph, startID int, targetIDs []int, k int) []TargetDistance {
+	nearest := []TargetDistance{}
+	targetIDs = existingNodes(g, targetIDs)
+	component := smallestComponentOf(g, append([]int{startID}, targetIDs...))
+	if component == nil || len(targetIDs) == 0 || k <= 0 {
+		return nearest
+	}
+	c := component.Dense()
+	start, _ := c.Index(startID)
+
+	isTarget := make(map[int]bool, len(targetIDs)) // dense index
+	for _, id := range targetIDs {
+		i, _ := c.Index(id)
+		isTarget[i] = true
+	}
+	multiSourceDijkstra(c, []int{start}, []float64{0}, nil, func(i int, cost float64) bool {
+		if isTarget[i] {
+			nearest = append(nearest, TargetDistance{c.NodeIDs[i], cost})
+		}
+		return len(nearest) < k
+	})
+	return nearest
+}
+
+// Returns nodes that are part of g, in their order
+func existingNodes(g *graph.Graph, nodes []int) []int {
+	existing := make([]int, 0, len(nodes))
+	for _, node := range nodes {
+		if _, exists := g.AdjList[node]; exists {
+			existing = append(existing, node)
+		}
+	}
+	return existing
+}
+
+// Returns smallest hierarchy component of g that contains all nodes, nil if a node is not part of g
+func smallestComponentOf(g *graph.Graph, nodes []int) *graph.Graph {
+	for _, node := range nodes {
+		if _, exists := g.AdjList[node]; !exists {
+			return nil
+		}
+	}
+	for {
+		index := slices.IndexFunc(g.Childs, func(child *graph.Graph) bool {
+			for _, node := range nodes {
+				if _, exists := child.AdjList[node]; !exists {
+					return false
+				}
+			}
+			return true
+		})
+		if index == -1 {
+			return g
+		}
+		g = g.Childs[index]
+	}
+}
@@ -0,0 +1,102 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNearestTarget(t *testing.T) {
+	/*
+		0  1  2  3
+		4  @  @  7
+		8  9  @  11
+	*/
+	g := graph.NewGraph(3, 4)
+	g.Grid = [][]int{
+		{0, 1, 2, 3},
+		{4, -1, -1, 7},
+		{8, 9, -1, 11},
+	}
+	g.BuildAdjlist()
+
+	tests := []struct {
+		start            int
+		targets          []int
+		expectedTarget   int
+		expectedDistance float64
+	}{
+		{0, []int{11, 9}, 9, 3},
+		{3, []int{11, 9}, 11, 2},
+		{2, []int{2, 9}, 2, 0},
+		{0, []int{}, -1, -1},
+		{0, []int{5}, -1, -1}, // obstacle
+		{0, []int{5, 11, 99, -1, 9}, 9, 3},
+		{5, []int{11, 9}, -1, -1}, // start on obstacle
+	}
+	for _, tt := range tests {
+		target, path, distance := NearestTarget(g, tt.start, tt.targets)
+		if target != tt.expectedTarget || distance != tt.expectedDistance {
+			t.Errorf("NearestTarget(%d, %v) = %d, %f, expected %d, %f", tt.start, tt.targets, target, distance, tt.expectedTarget, tt.expectedDistance)
+			continue
+		}
+		if target == -1 {
+			continue
+		}
+		if length, valid := PathLength(g, path); !valid || length != distance || path[0] != tt.start || path[len(path)-1] != target {
+			t.Errorf("Invalid path %v from %d to %d", path, tt.start, target)
+		}
+	}
+}
+
+func TestKNearestTargets(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+
+	targets := []int{35, 2, 24, 17}
+	for start := range g.AdjList {
+		nearest := KNearestTargets(g, start, targets, 3)
+		if len(nearest) != 3 {
+			t.Fatalf("Expected 3 targets, got %v", nearest)
+		}
+		for i, n := range nearest {
+			if expected := ShortestDistance(g, start, n.Target); math.Abs(n.Distance-expected) > 1e-9 {
+				t.Errorf("Distance %d -> %d is %f, expected %f", start, n.Target, n.Distance, expected)
+			}
+			if i > 0 && n.Distance < nearest[i-1].Distance {
+				t.Errorf("Targets of %d are not sorted by distance: %v", start, nearest)
+			}
+		}
+		target, _, distance := NearestTarget(g, start, targets)
+		if math.Abs(distance-nearest[0].Distance) > 1e-9 {
+			t.Errorf("Nearest target of %d is %d with %f, expected distance %f", start, target, distance, nearest[0].Distance)
+		}
+	}
+
+	// all targets in one child: the search is confined to it
+	child := g.Childs[0]
+	members := []int{}
+	for node := range child.AdjList {
+		members = append(members, node)
+	}
+	if component := smallestComponentOf(g, members); component != child {
+		t.Errorf("Expected child as smallest component of its members")
+	}
+	if nearest := KNearestTargets(g, 0, targets, 0); !reflect.DeepEqual(nearest, []TargetDistance{}) {
+		t.Errorf("Expected no targets for k = 0, got %v", nearest)
+	}
+
+	// invalid targets are dropped, obstacle 7 and ids outside of the grid
+	mixed := []int{7, 35, -1, 2, 99, 24, 17}
+	for start := range g.AdjList {
+		if got, expected := KNearestTargets(g, start, mixed, 3), KNearestTargets(g, start, targets, 3); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v with invalid targets from %d, got %v", expected, start, got)
+		}
+	}
+	if nearest := KNearestTargets(g, 0, []int{7, 99}, 3); !reflect.DeepEqual(nearest, []TargetDistance{}) {
+		t.Errorf("Expected no targets if all are invalid, got %v", nearest)
+	}
+	if nearest := KNearestTargets(g, 7, targets, 3); !reflect.DeepEqual(nearest, []TargetDistance{}) {
+		t.Errorf("Expected no targets for invalid start, got %v", nearest)
+	}
+}
@@ -0,0 +1,143 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// computes a shortest path between two nodes of a graph or hierarchy component (e.g. from FindSmallestConvexComponent)
+// returns the nodeids from startID to endID (both included) and the path length, nil and -1 if there is no path
+func ShortestPath(g *graph.Graph, startID, endID int) ([]int, float64) {
+	if g == nil {
+		return nil, -1
+	}
+	if g.Weighted() {
+		return DijkstraPath(g.Dense(), startID, endID)
+	}
+	path := BreadthFirstSearchPath(g.Dense(), startID, endID)
+	return path, float64(len(path) - 1)
+}
+
+// computes a shortest path between two nodes with BFS on the compact (CSR) form of a graph
+// returns the nodeids from startID to endID (both included), nil if endID is not reachable
+func BreadthFirstSearchPath(c *graph.CSR, startID, endID int) []int {
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return nil
+	}
+
+	// prev of visited nodes, -1 if not visited
+	prev := make([]int, c.Len())
+	for i := range prev {
+		prev[i] = -1
+	}
+	prev[start] = start
+	queue := make([]int, 0, c.Len())
+	queue = append(queue, start)
+
+	for head := 0; head < len(queue) && prev[end] == -1; head++ {
+		current := queue[head]
+		for _, neighbor := range c.Adjacent(current) {
+			if prev[neighbor] == -1 {
+				prev[neighbor] = current
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return tracePath(c, prev, start, end)
+}
+
+// computes a shortest path between two nodes with dijkstra on the compact (CSR) form of a graph
+// returns the nodeids from startID to endID (both included) and the path cost, nil and -1 if endID is not reachable
+func DijkstraPath(c *graph.CSR, startID, endID int) ([]int, float64) {
+	start, startExists := c.Index(startID)
+	end, endExists := c.Index(endID)
+	if !(startExists && endExists) {
+		return nil, -1
+	}
+
+	cost := make([]float64, c.Len())
+	prev := make([]int, c.Len())
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	settled := make([]bool, c.Len())
+	queue := graph.CostHeap{{Node: start, Cost: 0}}
+	cost[start] = 0
+	prev[start] = start
+
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(graph.CostItem)
+		// skip outdated entries
+		if settled[item.Node] {
+			continue
+		}
+		if item.Node == end {
+			return tracePath(c, prev, start, end), item.Cost
+		}
+		settled[item.Node] = true
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			newCost := item.Cost + 1
+			if weights != nil {
+				newCost = item.Cost + weights[k]
+			}
+			if !settled[neighbor] && newCost < cost[neighbor] {
+				cost[neighbor] = newCost
+				prev[neighbor] = item.Node
+				heap.Push(&queue, graph.CostItem{Node: neighbor, Cost: newCost})
+			}
+		}
+	}
+
+	// endID is not reachable from startID
+	return nil, -1
+}
+
+// follow prev (dense indices, start is its own prev) back from end, returns nodeids from start to end
+func tracePath(c *graph.CSR, prev []int, start, end int) []int {
+	if prev[end] == -1 {
+		return nil
+	}
+	path := []int{c.NodeIDs[end]}
+	for current := end; current != start; {
+		current = prev[current]
+		path = append(path, c.NodeIDs[current])
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Returns root grid coordinates (x,y) of the nodes of a path
+func PathCoordinates(g *graph.Graph, path []int) [][2]int {
+	coordinates := make([][2]int, len(path))
+	for i, node := range path {
+		x, y := g.Coordinates(node)
+		coordinates[i] = [2]int{x, y}
+	}
+	return coordinates
+}
+
+// Returns cost of a path in g and false if a node of the path is not part of g or two consecutive nodes are not adjacent
+func PathLength(g *graph.Graph, path []int) (float64, bool) {
+	length := 0.0
+	for i, node := range path {
+		if _, exists := g.AdjList[node]; !exists {
+			return -1, false
+		}
+		if i == 0 {
+			continue
+		}
+		if !slices.Contains(g.AdjList[path[i-1]], node) {
+			return -1, false
+		}
+		length += g.EdgeCost(path[i-1], node)
+	}
+	return length, true
+}
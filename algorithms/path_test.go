@@ -0,0 +1,117 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestShortestPath(t *testing.T) {
+	/*
+		0  1  2
+		3  @  5
+		6  7  8
+	*/
+	grid := [][]int{
+		{0, 1, 2},
+		{3, -1, 5},
+		{6, 7, 8},
+	}
+	testCases := []struct {
+		name           string
+		metric         *graph.Metric
+		start, end     int
+		expectedLength float64
+	}{
+		{"Unit", nil, 0, 8, 4},
+		{"Unit same node", nil, 3, 3, 0},
+		{"Octile", &graph.Metric{Octile: true}, 0, 8, 4},
+		{"Octile no corner cutting", &graph.Metric{Octile: true}, 3, 7, 2},
+		{"Terrain detour", &graph.Metric{Cost: []float64{1, 9, 1, 1, 1, 1, 1, 1, 1}}, 0, 2, 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := graph.NewGraph(3, 3)
+			g.Grid = grid
+			g.Metric = tc.metric
+			g.BuildAdjlist()
+
+			path, length := ShortestPath(g, tc.start, tc.end)
+			if math.Abs(length-tc.expectedLength) > 1e-9 {
+				t.Errorf("Expected length %f, got %f", tc.expectedLength, length)
+			}
+			if len(path) == 0 || path[0] != tc.start || path[len(path)-1] != tc.end {
+				t.Fatalf("Expected path from %d to %d, got %v", tc.start, tc.end, path)
+			}
+			cost, valid := PathLength(g, path)
+			if !valid || math.Abs(cost-length) > 1e-9 {
+				t.Errorf("Path %v is not valid or has cost %f instead of %f", path, cost, length)
+			}
+		})
+	}
+}
+
+func TestShortestPathUnreachable(t *testing.T) {
+	g := graph.NewGraph(1, 3)
+	g.Grid = [][]int{{0, -1, 2}}
+	g.BuildAdjlist()
+
+	if path, length := ShortestPath(g, 0, 2); path != nil || length != -1 {
+		t.Errorf("Expected no path, got %v with length %f", path, length)
+	}
+	if path, length := ShortestPath(g, 0, 1); path != nil || length != -1 {
+		t.Errorf("Expected no path to obstacle, got %v with length %f", path, length)
+	}
+	if path, length := ShortestPath(nil, 0, 2); path != nil || length != -1 {
+		t.Errorf("Expected no path without component, got %v with length %f", path, length)
+	}
+}
+
+func TestPathLength(t *testing.T) {
+	g := graph.NewGraph(2, 2)
+	g.Grid = [][]int{{0, 1}, {2, 3}}
+	g.BuildAdjlist()
+
+	tests := []struct {
+		path     []int
+		expected float64
+		valid    bool
+	}{
+		{[]int{0, 1, 3}, 2, true},
+		{[]int{0}, 0, true},
+		{[]int{0, 3}, -1, false}, // diagonal in 4-connected grid
+		{[]int{0, 7}, -1, false},
+	}
+	for _, tt := range tests {
+		length, valid := PathLength(g, tt.path)
+		if length != tt.expected || valid != tt.valid {
+			t.Errorf("PathLength(%v) = %f, %v, expected %f, %v", tt.path, length, valid, tt.expected, tt.valid)
+		}
+	}
+
+	if coordinates := PathCoordinates(g, []int{0, 1, 3}); !reflect.DeepEqual(coordinates, [][2]int{{0, 0}, {1, 0}, {1, 1}}) {
+		t.Errorf("Unexpected coordinates %v", coordinates)
+	}
+}
+
+func TestShortestPathInComponent(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+
+	for start := range g.AdjList {
+		for end := range g.AdjList {
+			component := FindSmallestConvexComponent(g, start, end)
+			path, length := ShortestPath(component, start, end)
+			for _, node := range path {
+				if _, exists := component.AdjList[node]; !exists {
+					t.Errorf("Path %d -> %d leaves its component at node %d", start, end, node)
+				}
+			}
+			cost, valid := PathLength(g, path)
+			if !valid || math.Abs(cost-length) > 1e-9 || math.Abs(length-ShortestDistance(g, start, end)) > 1e-9 {
+				t.Errorf("Path %d -> %d is not a shortest path: %v with cost %f", start, end, path, cost)
+			}
+		}
+	}
+}
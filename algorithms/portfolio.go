@@ -0,0 +1,63 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Starts all heuristics at once with a shared context, the first valid decomposition wins and cancels the others.
+// Which heuristic wins depends on timing, so unlike the sequential pipeline the hierarchy is not repeatable
+func portfolio(g *graph.Graph, sepFuncs []namedSep, provenance *graph.Provenance) []*graph.Graph {
+	shared, cancelAll := context.WithCancel(context.Background())
+	defer cancelAll()
+
+	var winner []*graph.Graph
+	attempts := runConcurrently(shared, g, sepFuncs, func(i int, childs []*graph.Graph) {
+		// a later valid decomposition is recorded, but the first one is kept
+		if winner == nil {
+			winner = childs
+			provenance.Heuristic = sepFuncs[i].name
+			cancelAll()
+		}
+	})
+	provenance.Attempts = append(provenance.Attempts, attempts...)
+	return winner
+}
+
+// Runs all heuristics at once below parent, found is called one at a time for every valid decomposition.
+// Returns the attempts in pipeline order after every heuristic stopped, because decomposeNode drops the grid of g afterwards
+func runConcurrently(parent context.Context, g *graph.Graph, sepFuncs []namedSep, found func(i int, childs []*graph.Graph)) []graph.Attempt {
+	startTime := time.Now()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	attempts := make([]graph.Attempt, len(sepFuncs))
+	for i, sepFunc := range sepFuncs {
+		ctx, cancel := heuristicContext(parent, g, sepFunc.name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer cancel()
+			childs, ok := sepFunc.f(g, ctx)
+			stopped := ctx.Err()
+
+			mu.Lock()
+			defer mu.Unlock()
+			attempt := graph.Attempt{Heuristic: sepFunc.name, Outcome: graph.OutcomeRejected, Time: time.Since(startTime)}
+			switch {
+			case ok:
+				attempt.Outcome = graph.OutcomeSuccess
+				found(i, childs)
+			case errors.Is(stopped, context.DeadlineExceeded):
+				attempt.Outcome = graph.OutcomeTimeout
+			case stopped != nil:
+				attempt.Outcome = graph.OutcomeCancelled
+			}
+			attempts[i] = attempt
+		}()
+	}
+	wg.Wait()
+	return attempts
+}
@@ -0,0 +1,94 @@
+package algorithms
+
+import (
+	"bachelor-project/config"
+	"bachelor-project/graph"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPortfolio(t *testing.T) {
+	defer func(pipeline []string, timeouts map[string]time.Duration, mode string) {
+		config.Pipeline, config.HeuristicTime, config.PipelineMode = pipeline, timeouts, mode
+	}(config.Pipeline, config.HeuristicTime, config.PipelineMode)
+
+	slow, reject := "test-slow", "test-reject"
+	registerTestSeparator(t, slow, func(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
+		<-ctx.Done()
+		return nil, false
+	})
+	registerTestSeparator(t, reject, func(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
+		return nil, false
+	})
+	if err := SetPipelineMode(PipelinePortfolio); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := SetPipelineMode("fastest"); err == nil || config.PipelineMode != PipelinePortfolio {
+		t.Errorf("Expected error for unknown mode and mode to stay portfolio, got %v, %q", err, config.PipelineMode)
+	}
+
+	newGraph := func() *graph.Graph {
+		g := graph.NewGraph(3, 5)
+		g.Grid = [][]int{
+			{0, 1, 2, 3, 4},
+			{5, 6, 7, 8, 9},
+			{10, 11, 12, 13, 14},
+		}
+		g.BuildAdjlist()
+		return g
+	}
+
+	testCases := []struct {
+		name      string
+		pipeline  string
+		heuristic string
+		outcomes  []graph.Outcome
+	}{
+		{"Winner cancels the rest", slow + "," + reject + ",rowcol", "rowcol",
+			[]graph.Outcome{graph.OutcomeCancelled, graph.OutcomeRejected, graph.OutcomeSuccess}},
+		{"No winner", slow + ":10ms," + reject, "",
+			[]graph.Outcome{graph.OutcomeTimeout, graph.OutcomeRejected}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetPipeline(tc.pipeline); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			g := newGraph()
+			startTime := time.Now()
+			childs := pipeline(g, loadSplitScore())
+			if runTime := time.Since(startTime); runTime > 10*time.Second {
+				t.Errorf("Expected slow heuristic to be stopped, took %v", runTime)
+			}
+
+			p := g.Provenance()
+			if p.Heuristic != tc.heuristic || (childs != nil) != (tc.heuristic != "") {
+				t.Errorf("Expected winner %q, got %q with %d childs", tc.heuristic, p.Heuristic, len(childs))
+			}
+			outcomes := []graph.Outcome{}
+			for i, attempt := range p.Attempts {
+				outcomes = append(outcomes, attempt.Outcome)
+				if attempt.Heuristic != config.Pipeline[i] {
+					t.Errorf("Expected attempts in pipeline order, got %s at %d", attempt.Heuristic, i)
+				}
+			}
+			if !reflect.DeepEqual(outcomes, tc.outcomes) {
+				t.Errorf("Expected outcomes %v, got %v", tc.outcomes, outcomes)
+			}
+		})
+	}
+
+	// whole hierarchy in portfolio mode
+	if err := SetPipeline("osp,tsp,rowcol,holecutting"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g := newGraph()
+	BuildConvexHierarchy(g)
+	CheckHierarchyValid(t, g, config.Alpha)
+	if g.Provenance() == nil || g.Provenance().Heuristic == "" {
+		t.Errorf("Expected winner of root split, got %+v", g.Provenance())
+	}
+}
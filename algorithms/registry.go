@@ -0,0 +1,137 @@
+package algorithms
+
+import (
+	"bachelor-project/algorithms/separators"
+	"bachelor-project/config"
+	"bachelor-project/graph"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Heuristic that splits g into alpha balanced convex childs, returns false if it found none.
+// It has to stop when ctx is done
+type SeparatorFunc func(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool)
+
+// separator functions by name, config.Pipeline selects from them
+var registry = struct {
+	sync.RWMutex
+	funcs map[string]SeparatorFunc
+}{funcs: map[string]SeparatorFunc{
+	"kaffpa":      separators.KaFFPaSeparator,
+	"osp":         separators.OneShortestPath,
+	"tsp":         separators.TwoShortestPath,
+	"rowcol":      separators.RowColumn,
+	"holecutting": separators.HoleCutting,
+	"guesscheck":  separators.GuessAndCheck,
+}}
+
+// Register a separator function under a name so it can be used in config.Pipeline.
+// Names may not be empty, contain ',' or ':' or be registered already
+func RegisterSeparator(name string, f SeparatorFunc) error {
+	if name == "" || strings.ContainsAny(name, ",:") {
+		return fmt.Errorf("invalid separator name %q", name)
+	}
+	if f == nil {
+		return fmt.Errorf("separator %q has no function", name)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, exists := registry.funcs[name]; exists {
+		return fmt.Errorf("separator %q is already registered", name)
+	}
+	registry.funcs[name] = f
+	return nil
+}
+
+// remove a registered separator function, used by tests to clean up the global registry
+func unregisterSeparator(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.funcs, name)
+}
+
+// Returns names of all registered separator functions, sorted
+func Separators() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.funcs))
+	for name := range registry.funcs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Set config.Pipeline and config.HeuristicTime from a list like "kaffpa:10s,osp,rowcol".
+// Heuristics are tried in the given order, a duration after the name replaces config.Time for this heuristic
+func SetPipeline(list string) error {
+	names := []string{}
+	timeouts := map[string]time.Duration{}
+	for _, entry := range strings.Split(list, ",") {
+		name, timeout, hasTimeout := strings.Cut(strings.TrimSpace(entry), ":")
+		if _, exists := lookupSeparator(name); !exists {
+			return fmt.Errorf("unknown separator %q, registered are %s", name, strings.Join(Separators(), ", "))
+		}
+		if slices.Contains(names, name) {
+			return fmt.Errorf("separator %q is listed twice", name)
+		}
+		if hasTimeout {
+			duration, err := time.ParseDuration(timeout)
+			if err != nil || duration <= 0 {
+				return fmt.Errorf("invalid timeout %q of separator %q", timeout, name)
+			}
+			timeouts[name] = duration
+		}
+		names = append(names, name)
+	}
+	config.Pipeline = names
+	config.HeuristicTime = timeouts
+	return nil
+}
+
+// Modes of the pipeline, see config.PipelineMode
+const (
+	PipelineSequential = "sequential"
+	PipelinePortfolio  = "portfolio"
+	PipelineBest       = "best"
+)
+
+// Set config.PipelineMode
+func SetPipelineMode(mode string) error {
+	switch mode {
+	case PipelineSequential, PipelinePortfolio, PipelineBest:
+		config.PipelineMode = mode
+		return nil
+	}
+	return fmt.Errorf("unknown pipeline mode %q, expected %s, %s or %s", mode, PipelineSequential, PipelinePortfolio, PipelineBest)
+}
+
+func lookupSeparator(name string) (SeparatorFunc, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	f, exists := registry.funcs[name]
+	return f, exists
+}
+
+// separator functions of config.Pipeline in order, names that are not registered are skipped
+func pipelineSeparators() []namedSep {
+	sepFuncs := make([]namedSep, 0, len(config.Pipeline))
+	for _, name := range config.Pipeline {
+		if f, exists := lookupSeparator(name); exists {
+			sepFuncs = append(sepFuncs, namedSep{name, f})
+		}
+	}
+	return sepFuncs
+}
+
+// time limit of a heuristic, config.Time if it has none of its own
+func heuristicTime(name string) time.Duration {
+	if timeout, exists := config.HeuristicTime[name]; exists {
+		return timeout
+	}
+	return config.Time
+}
@@ -0,0 +1,93 @@
+package algorithms
+
+import (
+	"bachelor-project/config"
+	"bachelor-project/graph"
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSetPipeline(t *testing.T) {
+	defer func(pipeline []string, timeouts map[string]time.Duration) {
+		config.Pipeline, config.HeuristicTime = pipeline, timeouts
+	}(config.Pipeline, config.HeuristicTime)
+
+	if err := SetPipeline("kaffpa:10s, osp,rowcol"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"kaffpa", "osp", "rowcol"}; !reflect.DeepEqual(config.Pipeline, expected) {
+		t.Errorf("Expected pipeline %v, got %v", expected, config.Pipeline)
+	}
+	if heuristicTime("kaffpa") != 10*time.Second || heuristicTime("osp") != config.Time {
+		t.Errorf("Expected 10s for kaffpa and config.Time for osp, got %v and %v", heuristicTime("kaffpa"), heuristicTime("osp"))
+	}
+
+	for _, list := range []string{"", "osp,unknown", "osp,osp", "osp:fast", "osp:-1s"} {
+		if err := SetPipeline(list); err == nil {
+			t.Errorf("Expected error for pipeline %q", list)
+		}
+	}
+	if !reflect.DeepEqual(config.Pipeline, []string{"kaffpa", "osp", "rowcol"}) {
+		t.Errorf("Expected invalid pipelines to keep the previous one, got %v", config.Pipeline)
+	}
+}
+
+func TestRegisterSeparator(t *testing.T) {
+	defer func(pipeline []string, timeouts map[string]time.Duration) {
+		config.Pipeline, config.HeuristicTime = pipeline, timeouts
+	}(config.Pipeline, config.HeuristicTime)
+
+	// never finishes before its time limit
+	blocking := func(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
+		<-ctx.Done()
+		return nil, false
+	}
+	name := "test-blocking"
+	if err := RegisterSeparator(name, blocking); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { unregisterSeparator(name) }) // registry outlives a test run
+	for _, invalid := range []string{name, "osp", "", "a,b", "a:b"} {
+		if err := RegisterSeparator(invalid, blocking); err == nil {
+			t.Errorf("Expected error for name %q", invalid)
+		}
+	}
+	if err := RegisterSeparator("test-nil", nil); err == nil {
+		t.Errorf("Expected error for nil function")
+	}
+	if !slices.Contains(Separators(), name) || !slices.IsSorted(Separators()) {
+		t.Errorf("Expected sorted names with registered separator, got %v", Separators())
+	}
+
+	if err := SetPipeline(name + ":10ms,rowcol"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g := graph.NewGraph(3, 5)
+	g.Grid = [][]int{
+		{0, 1, 2, 3, 4},
+		{5, 6, 7, 8, 9},
+		{10, 11, 12, 13, 14},
+	}
+	g.BuildAdjlist()
+	BuildConvexHierarchy(g)
+
+	p := g.Provenance()
+	if p == nil || len(p.Attempts) != 2 || p.Heuristic != "rowcol" {
+		t.Fatalf("Expected split by rowcol after blocking heuristic, got %+v", p)
+	}
+	if first := p.Attempts[0]; first.Heuristic != name || first.Outcome != graph.OutcomeTimeout || first.Time > time.Second {
+		t.Errorf("Expected timeout of blocking heuristic after 10ms, got %+v", first)
+	}
+}
+
+// register f for the duration of the test
+func registerTestSeparator(t *testing.T, name string, f SeparatorFunc) {
+	t.Helper()
+	if err := RegisterSeparator(name, f); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { unregisterSeparator(name) })
+}
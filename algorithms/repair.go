@@ -0,0 +1,136 @@
+package algorithms
+
+import (
+	"bachelor-project/config"
+	"bachelor-project/graph"
+	"bachelor-project/graphdecomp"
+	"context"
+)
+
+// Summary of a hierarchy repair
+type RepairReport struct {
+	Opened       []int          // nodeids that became passable
+	Closed       []int          // nodeids that became obstacles
+	Checked      int            // number of hierarchy nodes whose decomposition was verified
+	Rebuilt      []*graph.Graph // hierarchy nodes whose subtree was rebuilt
+	RebuiltNodes int            // number of hierarchy nodes created by the rebuilds
+}
+
+// Toggles passability of root grid cells (x,y) of a built hierarchy and repairs the hierarchy.
+// Only decompositions that contain changed cells are verified, every hierarchy node whose
+// decomposition is no longer convex, balanced or connected is rebuilt with the pipeline
+func RepairHierarchy(g *graph.Graph, cells [][2]int) RepairReport {
+	opened, closed, changed := g.ToggleCells(cells)
+	report := RepairReport{Opened: opened, Closed: closed}
+	if len(opened)+len(closed) == 0 {
+		return report
+	}
+
+	// nodes whose adjacency changed or that were removed
+	affected := make(map[int]struct{}, len(changed)+len(closed))
+	for _, node := range changed {
+		affected[node] = struct{}{}
+	}
+	for _, node := range closed {
+		affected[node] = struct{}{}
+	}
+
+	repairNode(g, g, opened, affected, &report)
+	return report
+}
+
+// verify decomposition of hierarchy node g with up to date adjacency list and repair it
+// opened are the opened nodes inside of g
+func repairNode(root, g *graph.Graph, opened []int, affected map[int]struct{}, report *RepairReport) {
+	if len(g.Childs) == 0 {
+		return
+	}
+
+	// opened node belongs to the child of all its neighbors, otherwise it is a separator node of g
+	assigned := make([][]int, len(g.Childs))
+	for _, node := range opened {
+		owner := -1
+		for _, neighbor := range g.AdjList[node] {
+			for i, child := range g.Childs {
+				if _, exists := child.AdjList[neighbor]; exists {
+					if owner == -1 {
+						owner = i
+					} else if owner != i {
+						owner = -2
+					}
+				}
+			}
+		}
+		if owner >= 0 {
+			assigned[owner] = append(assigned[owner], node)
+		}
+	}
+
+	// update node sets and adjacency lists of touched childs
+	touched := make([]bool, len(g.Childs))
+	for i, child := range g.Childs {
+		touched[i] = len(assigned[i]) > 0
+		for node := range affected {
+			if _, exists := child.AdjList[node]; exists {
+				touched[i] = true
+				break
+			}
+		}
+		if !touched[i] {
+			continue
+		}
+		nodes := make([]int, 0, len(child.AdjList)+len(assigned[i]))
+		for node := range child.AdjList {
+			if _, exists := g.AdjList[node]; exists { // closed nodes are gone in g
+				nodes = append(nodes, node)
+			}
+		}
+		child.AdjList = graph.InducedAdjlist(g.AdjList, append(nodes, assigned[i]...))
+		child.ResetDense()
+	}
+	setSeparator(g)
+	if p := g.Provenance(); p != nil {
+		p.Balance = balanceOf(g)
+	}
+
+	report.Checked++
+	ctx, cancel := context.WithTimeout(context.Background(), config.Time)
+	valid := graphdecomp.CheckDecomposition(g, g.Childs, ctx)
+	cancel()
+	if !valid {
+		rebuild(root, g, report)
+		return
+	}
+
+	for i, child := range g.Childs {
+		if touched[i] {
+			repairNode(root, child, assigned[i], affected, report)
+		}
+	}
+}
+
+// rebuild the subtree of hierarchy node g
+func rebuild(root, g *graph.Graph, report *RepairReport) {
+	if g == root {
+		BuildConvexHierarchy(g)
+	} else {
+		graphdecomp.RestoreGrid(root, g)
+		score := loadSplitScore()
+		g.Childs = pipeline(g, score)
+		g.Grid = nil
+		g.ResetDense()
+		setSeparator(g)
+		buildSubtrees(g.Childs, score)
+	}
+	report.Rebuilt = append(report.Rebuilt, g)
+	report.RebuiltNodes += countNodes(g) - 1
+}
+
+// Returns number of hierarchy nodes in the tree of g
+func countNodes(g *graph.Graph) int {
+	count := 1
+	for _, child := range g.Childs {
+		count += countNodes(child)
+	}
+	return count
+}
@@ -0,0 +1,85 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"bachelor-project/graphdecomp"
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// checks every decomposition of the hierarchy and the adjacency lists against the root
+func checkHierarchy(t *testing.T, root, g *graph.Graph) {
+	t.Helper()
+	for node, neighbors := range g.AdjList {
+		if _, exists := root.AdjList[node]; !exists {
+			t.Errorf("Node %d is not passable in root", node)
+		}
+		for _, neighbor := range neighbors {
+			if _, exists := g.AdjList[neighbor]; !exists {
+				t.Errorf("Neighbor %d of node %d is not part of the component", neighbor, node)
+			}
+		}
+	}
+	if len(g.Childs) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if !graphdecomp.CheckDecomposition(g, g.Childs, ctx) {
+		t.Errorf("Invalid decomposition of component with %d nodes", len(g.AdjList))
+	}
+	for _, child := range g.Childs {
+		checkHierarchy(t, root, child)
+	}
+}
+
+func TestRepairHierarchy(t *testing.T) {
+	grid := [][]int{
+		{0, 1, 2, 3, 4, 5, 6, 7},
+		{8, -1, 10, 11, -1, 13, 14, 15},
+		{16, 17, 18, 19, 20, 21, -1, 23},
+		{24, 25, -1, 27, 28, 29, 30, 31},
+		{32, 33, 34, 35, -1, 37, 38, 39},
+		{40, -1, 42, 43, 44, 45, 46, 47},
+	}
+	// octile mode, every decomposition of the pipeline is checked for convexity without shortcuts
+	g := graph.NewGraph(6, 8)
+	g.Grid = grid
+	g.Metric = &graph.Metric{Octile: true}
+	g.BuildAdjlist()
+	BuildConvexHierarchy(g)
+
+	// no change for cells outside of the grid
+	report := RepairHierarchy(g, [][2]int{{-1, 0}, {8, 2}})
+	if len(report.Opened)+len(report.Closed) != 0 || report.Checked != 0 {
+		t.Errorf("Expected empty report, got %+v", report)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for range 10 {
+		cells := [][2]int{{random.Intn(8), random.Intn(6)}, {random.Intn(8), random.Intn(6)}}
+		report := RepairHierarchy(g, cells)
+		if len(report.Opened)+len(report.Closed) == 0 {
+			t.Fatalf("Expected toggled cells for %v", cells)
+		}
+		checkHierarchy(t, g, g)
+		checkSeparators(t, g)
+		if report.Checked == 0 && len(report.Rebuilt) == 0 {
+			t.Errorf("Expected verified or rebuilt components after toggling %v", cells)
+		}
+
+		// distances in the smallest convex component must stay exact
+		for start := range g.AdjList {
+			for end := range g.AdjList {
+				component := FindSmallestConvexComponent(g, start, end)
+				expected := ShortestDistance(g, start, end)
+				if got := ShortestDistance(component, start, end); math.Abs(got-expected) > 1e-9 {
+					t.Errorf("After toggling %v distance %d -> %d in component is %f, expected %f", cells, start, end, got, expected)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package algorithms
+
+import (
+	"bachelor-project/config"
+	"bachelor-project/graph"
+	"context"
+	"sync/atomic"
+)
+
+// Score of a decomposition of g into childs, the pipeline in best mode takes the lowest score
+type SplitScoreFunc func(g *graph.Graph, childs []*graph.Graph) float64
+
+// score of builds without their own, nil means DefaultSplitScore
+var splitScore atomic.Pointer[SplitScoreFunc]
+
+// Set the score of the best mode for the following builds, nil restores DefaultSplitScore.
+// A running build keeps the score it started with
+func SetSplitScore(f SplitScoreFunc) {
+	if f == nil {
+		splitScore.Store(nil)
+		return
+	}
+	splitScore.Store(&f)
+}
+
+// score set with SetSplitScore
+func loadSplitScore() SplitScoreFunc {
+	if f := splitScore.Load(); f != nil {
+		return *f
+	}
+	return DefaultSplitScore
+}
+
+// (separator nodes + nodes of the largest child + childs) / nodes of g.
+// Small separators and balanced splits score low, every child costs as much as one separator node
+func DefaultSplitScore(g *graph.Graph, childs []*graph.Graph) float64 {
+	nodes := len(g.AdjList)
+	if nodes == 0 {
+		return 0
+	}
+	separator, largest := nodes, 0
+	for _, child := range childs {
+		separator -= len(child.AdjList)
+		largest = max(largest, len(child.AdjList))
+	}
+	return float64(separator+largest+len(childs)) / float64(nodes)
+}
+
+// Starts all heuristics at once and waits until all finished or config.SelectionTime is over,
+// the valid decomposition with the lowest score wins, ties go to the heuristic first in the pipeline
+func best(g *graph.Graph, sepFuncs []namedSep, provenance *graph.Provenance, score SplitScoreFunc) []*graph.Graph {
+	budget, cancel := context.WithTimeout(context.Background(), config.SelectionTime)
+	defer cancel()
+
+	var winner []*graph.Graph
+	winnerIndex, winnerScore := -1, 0.0
+	attempts := runConcurrently(budget, g, sepFuncs, func(i int, childs []*graph.Graph) {
+		childScore := score(g, childs)
+		if winnerIndex == -1 || childScore < winnerScore || (childScore == winnerScore && i < winnerIndex) {
+			winner, winnerIndex, winnerScore = childs, i, childScore
+		}
+	})
+	if winner != nil {
+		provenance.Heuristic = sepFuncs[winnerIndex].name
+	}
+	provenance.Attempts = append(provenance.Attempts, attempts...)
+	return winner
+}
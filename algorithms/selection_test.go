@@ -0,0 +1,191 @@
+package algorithms
+
+import (
+	"bachelor-project/config"
+	"bachelor-project/graph"
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDefaultSplitScore(t *testing.T) {
+	// 0 1 2
+	// 3 4 5
+	g := graph.NewGraph(2, 3)
+	g.Grid = [][]int{{0, 1, 2}, {3, 4, 5}}
+	g.BuildAdjlist()
+	induced := func(nodes ...int) *graph.Graph {
+		return &graph.Graph{AdjList: graph.InducedAdjlist(g.AdjList, nodes)}
+	}
+
+	testCases := []struct {
+		name     string
+		childs   []*graph.Graph
+		expected float64
+	}{
+		{"Middle column", []*graph.Graph{induced(0, 3), induced(2, 5)}, (2 + 2 + 2) / 6.0},
+		{"Unbalanced", []*graph.Graph{induced(0), induced(2, 5, 4)}, (2 + 3 + 2) / 6.0},
+		{"Only separator", []*graph.Graph{}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if score := DefaultSplitScore(g, tc.childs); math.Abs(score-tc.expected) > 1e-9 {
+				t.Errorf("Expected score %g, got %g", tc.expected, score)
+			}
+		})
+	}
+}
+
+func TestBestSelection(t *testing.T) {
+	defer func(pipeline []string, timeouts map[string]time.Duration, mode string, budget time.Duration) {
+		config.Pipeline, config.HeuristicTime, config.PipelineMode, config.SelectionTime = pipeline, timeouts, mode, budget
+		SetSplitScore(nil)
+	}(config.Pipeline, config.HeuristicTime, config.PipelineMode, config.SelectionTime)
+
+	//  0  1  2  3  4
+	//  5  6  7  8  9
+	// 10 11 12 13 14
+	newGraph := func() *graph.Graph {
+		g := graph.NewGraph(3, 5)
+		g.Grid = [][]int{
+			{0, 1, 2, 3, 4},
+			{5, 6, 7, 8, 9},
+			{10, 11, 12, 13, 14},
+		}
+		g.BuildAdjlist()
+		return g
+	}
+	split := func(parts ...[]int) SeparatorFunc {
+		return func(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
+			childs := []*graph.Graph{}
+			for _, nodes := range parts {
+				childs = append(childs, &graph.Graph{AdjList: graph.InducedAdjlist(g.AdjList, nodes)})
+			}
+			return childs, true
+		}
+	}
+
+	row, column, column2, slow := "test-row", "test-column", "test-column2", "test-slow"
+	registerTestSeparator(t, row, split([]int{0, 1, 2, 3, 4}, []int{10, 11, 12, 13, 14}))         // score (5+5+2)/15
+	registerTestSeparator(t, column, split([]int{0, 1, 5, 6, 10, 11}, []int{3, 4, 8, 9, 13, 14})) // score (3+6+2)/15
+	registerTestSeparator(t, column2, split([]int{0, 1, 5, 6, 10, 11}, []int{3, 4, 8, 9, 13, 14}))
+	registerTestSeparator(t, slow, func(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
+		<-ctx.Done()
+		return nil, false
+	})
+	if err := SetPipelineMode(PipelineBest); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config.SelectionTime = 50 * time.Millisecond
+
+	testCases := []struct {
+		name      string
+		pipeline  string
+		score     SplitScoreFunc
+		heuristic string
+		outcomes  []graph.Outcome
+	}{
+		{"Smallest separator wins", row + "," + column, nil, column,
+			[]graph.Outcome{graph.OutcomeSuccess, graph.OutcomeSuccess}},
+		{"Tie goes to first in pipeline", column2 + "," + column, nil, column2,
+			[]graph.Outcome{graph.OutcomeSuccess, graph.OutcomeSuccess}},
+		{"Budget stops slow heuristic", slow + "," + row, nil, row,
+			[]graph.Outcome{graph.OutcomeTimeout, graph.OutcomeSuccess}},
+		{"Own score", column + "," + row, func(g *graph.Graph, childs []*graph.Graph) float64 {
+			return -DefaultSplitScore(g, childs)
+		}, row, []graph.Outcome{graph.OutcomeSuccess, graph.OutcomeSuccess}},
+		{"No candidate", slow, nil, "", []graph.Outcome{graph.OutcomeTimeout}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetPipeline(tc.pipeline); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			SetSplitScore(tc.score)
+			g := newGraph()
+			childs := pipeline(g, loadSplitScore())
+
+			p := g.Provenance()
+			if p.Heuristic != tc.heuristic || (childs != nil) != (tc.heuristic != "") {
+				t.Errorf("Expected winner %q, got %q with %d childs", tc.heuristic, p.Heuristic, len(childs))
+			}
+			if len(p.Attempts) != len(tc.outcomes) {
+				t.Fatalf("Expected %d attempts, got %+v", len(tc.outcomes), p.Attempts)
+			}
+			for i, attempt := range p.Attempts {
+				if attempt.Heuristic != config.Pipeline[i] || attempt.Outcome != tc.outcomes[i] {
+					t.Errorf("Expected %s with outcome %v at %d, got %+v", config.Pipeline[i], tc.outcomes[i], i, attempt)
+				}
+			}
+		})
+	}
+
+	// whole hierarchy in best mode
+	SetSplitScore(nil)
+	config.SelectionTime = 10 * time.Second
+	if err := SetPipeline("osp,tsp,rowcol,holecutting"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g := newGraph()
+	BuildConvexHierarchy(g)
+	CheckHierarchyValid(t, g, config.Alpha)
+}
+
+func TestBuildConvexHierarchyScored(t *testing.T) {
+	defer func(pipeline []string, timeouts map[string]time.Duration, mode string) {
+		config.Pipeline, config.HeuristicTime, config.PipelineMode = pipeline, timeouts, mode
+		SetSplitScore(nil)
+	}(config.Pipeline, config.HeuristicTime, config.PipelineMode)
+	if err := SetPipeline("osp,rowcol,holecutting"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config.PipelineMode = PipelineBest
+
+	// counts its calls and scores like DefaultSplitScore
+	counting := func(calls *atomic.Int64) SplitScoreFunc {
+		return func(g *graph.Graph, childs []*graph.Graph) float64 {
+			calls.Add(1)
+			return DefaultSplitScore(g, childs)
+		}
+	}
+	var global, own [2]atomic.Int64
+	SetSplitScore(counting(&global[0]))
+
+	// builds with their own score next to changes of the global score
+	var wg sync.WaitGroup
+	for i := range own {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			g := graph.NewGraph(4, 5)
+			g.Grid = [][]int{
+				{0, 1, 2, 3, 4},
+				{5, 6, 7, 8, 9},
+				{10, 11, 12, 13, 14},
+				{15, 16, 17, 18, 19},
+			}
+			g.BuildAdjlist()
+			BuildConvexHierarchyScored(g, 2, counting(&own[i]))
+			CheckHierarchyValid(t, g, config.Alpha)
+		}()
+		go func() {
+			defer wg.Done()
+			SetSplitScore(counting(&global[1]))
+		}()
+	}
+	wg.Wait()
+
+	for i := range own {
+		if own[i].Load() == 0 {
+			t.Errorf("Expected build %d to use its own score", i)
+		}
+	}
+	if global[0].Load() != 0 || global[1].Load() != 0 {
+		t.Errorf("Expected global score to be unused, got %d and %d calls", global[0].Load(), global[1].Load())
+	}
+}
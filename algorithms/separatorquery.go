@@ -0,0 +1,140 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"container/heap"
+	"math"
+)
+
+// computes the exact distance between two nodes through the separator of their smallest convex component.
+// If start and end lie in different childs (or on the separator), every path between them crosses the separator, so
+//
+//	d(s,t) = min over separator nodes u, w of  d_A(s,u) + d(u,w) + d_B(w,t)
+//
+// with u the first and w the last separator node of a shortest path. d_A(s,u) is computed with a search bounded to the
+// child A of start and the separator, d_B(w,t) bounded to the child B of end and the separator, and d(u,w) is taken
+// from the distances between all separator nodes that are computed once per component (see splitIndexOf).
+// Returns -1 if a node is missing or end is not reachable
+func SeparatorDistance(g *graph.Graph, startID, endID int) float64 {
+	p := FindSmallestConvexComponent(g, startID, endID)
+	if p == nil {
+		return -1
+	}
+	if startID == endID {
+		return 0
+	}
+	if len(p.Childs) == 0 {
+		return ShortestDistance(p, startID, endID)
+	}
+
+	c := p.Dense()
+	index := splitIndexOf(p)
+	// child and separator nodes, only the separator if node is a separator node itself
+	region := func(node int) func(i int) bool {
+		child := index.Owner[node]
+		return func(i int) bool {
+			return index.Owner[i] == -1 || (child != -1 && index.Owner[i] == child)
+		}
+	}
+
+	start, _ := c.Index(startID)
+	end, _ := c.Index(endID)
+	fromStart := multiSourceDijkstra(c, []int{start}, []float64{0}, region(start), nil)
+	fromEnd := multiSourceDijkstra(c, []int{end}, []float64{0}, region(end), nil)
+
+	// separator nodes reachable from end, so the inner loop skips the others
+	reachedEnd := []int{}
+	for b, i := range index.Separator {
+		if !math.IsInf(fromEnd[i], 1) {
+			reachedEnd = append(reachedEnd, b)
+		}
+	}
+	best := math.Inf(1)
+	for a, i := range index.Separator {
+		if math.IsInf(fromStart[i], 1) || fromStart[i] >= best {
+			continue
+		}
+		for _, b := range reachedEnd {
+			best = min(best, fromStart[i]+index.Distances[a][b]+fromEnd[index.Separator[b]])
+		}
+	}
+
+	if math.IsInf(best, 1) {
+		return -1
+	}
+	return best
+}
+
+// Returns the split index of a split component with the distances between all its separator nodes,
+// it is built with one search per separator node on first use and cached in the component
+func splitIndexOf(p *graph.Graph) *graph.SplitIndex {
+	if index := p.SplitIndex(); index != nil {
+		return index
+	}
+	index := graph.NewSplitIndex(p)
+	c := p.Dense()
+	index.Distances = make([][]float64, len(index.Separator))
+	for a, i := range index.Separator {
+		fromSeparator := multiSourceDijkstra(c, []int{i}, []float64{0}, nil, nil)
+		index.Distances[a] = make([]float64, len(index.Separator))
+		for b, j := range index.Separator {
+			index.Distances[a][b] = fromSeparator[j]
+		}
+	}
+	p.SetSplitIndex(index)
+	return index
+}
+
+// Returns child of g that contains node, nil if node is a separator node
+func childOf(g *graph.Graph, node int) *graph.Graph {
+	for _, child := range g.Childs {
+		if _, exists := child.AdjList[node]; exists {
+			return child
+		}
+	}
+	return nil
+}
+
+// dijkstra from several sources with initial costs on dense indices. Only nodes with allowed(i) are visited (all if allowed is nil),
+// settle is called for every settled node in order of cost and stops the search by returning false (ignored if nil).
+// Returns cost of every node, +Inf if not reached
+func multiSourceDijkstra(c *graph.CSR, sources []int, initial []float64, allowed func(i int) bool, settle func(i int, cost float64) bool) []float64 {
+	cost := make([]float64, c.Len())
+	for i := range cost {
+		cost[i] = math.Inf(1)
+	}
+	settled := make([]bool, c.Len())
+	queue := graph.CostHeap{}
+	for k, source := range sources {
+		cost[source] = initial[k]
+		heap.Push(&queue, graph.CostItem{Node: source, Cost: initial[k]})
+	}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(graph.CostItem)
+		// skip outdated entries
+		if settled[item.Node] {
+			continue
+		}
+		settled[item.Node] = true
+		if settle != nil && !settle(item.Node, item.Cost) {
+			break
+		}
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			if allowed != nil && !allowed(neighbor) {
+				continue
+			}
+			newCost := item.Cost + 1
+			if weights != nil {
+				newCost = item.Cost + weights[k]
+			}
+			if !settled[neighbor] && newCost < cost[neighbor] {
+				cost[neighbor] = newCost
+				heap.Push(&queue, graph.CostItem{Node: neighbor, Cost: newCost})
+			}
+		}
+	}
+	return cost
+}
@@ -0,0 +1,58 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"testing"
+)
+
+func TestSeparatorDistance(t *testing.T) {
+	testCases := []struct {
+		name   string
+		metric *graph.Metric
+	}{
+		{"Unit", nil},
+		{"Octile", &graph.Metric{Octile: true}},
+		{"Octile terrain", &graph.Metric{Octile: true, Cost: testTerrain()}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestHierarchy(t, tc.metric)
+
+			for start := range g.AdjList {
+				for end := range g.AdjList {
+					// same distance as a search in the whole smallest component
+					expected := ShortestDistance(FindSmallestConvexComponent(g, start, end), start, end)
+					if got := SeparatorDistance(g, start, end); math.Abs(got-expected) > 1e-9 {
+						t.Errorf("Separator distance %d -> %d is %f, expected %f", start, end, got, expected)
+					}
+				}
+			}
+			// separator distances are computed once per component
+			if g.SplitIndex() == nil || splitIndexOf(g) != g.SplitIndex() {
+				t.Errorf("Expected cached split index of the root")
+			}
+			if SeparatorDistance(g, 0, 7) != -1 {
+				t.Errorf("Expected -1 for obstacle")
+			}
+		})
+	}
+}
+
+func TestSeparatorDistanceDisconnected(t *testing.T) {
+	g := graph.NewGraph(2, 3)
+	g.Grid = [][]int{
+		{0, -1, 2},
+		{3, -1, 5},
+	}
+	g.BuildAdjlist()
+	BuildConvexHierarchy(g)
+
+	if got := SeparatorDistance(g, 0, 5); got != -1 {
+		t.Errorf("Expected -1 between components, got %f", got)
+	}
+	if got := SeparatorDistance(g, 2, 5); got != 1 {
+		t.Errorf("Expected 1, got %f", got)
+	}
+}
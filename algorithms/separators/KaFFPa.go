@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"sort"
 	"strconv"
 	"strings"
 )
@@ -31,12 +30,8 @@ func KaFFPaSeparator(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool)
 	defer os.Remove(tmpOutputFile.Name())
 	defer tmpOutputFile.Close()
 
-	// gather original nodeids (old now)
-	oldIDs := []int{}
-	for id := range g.AdjList {
-		oldIDs = append(oldIDs, id)
-	}
-	sort.Ints(oldIDs) // for stabile order, and backtracking of original/old ids
+	// original nodeids (old now), the dense form is sorted for stabile order and backtracking of original/old ids
+	oldIDs := g.Dense().NodeIDs
 
 	idMap := make(map[int]int, len(oldIDs))
 	reverseMap := make(map[int]int, len(oldIDs))
@@ -4,6 +4,7 @@ import (
 	"bachelor-project/graph"
 	"bachelor-project/graphdecomp"
 	"context"
+	"slices"
 )
 
 func GuessAndCheck(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
@@ -12,6 +13,7 @@ func GuessAndCheck(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
 	for key := range g.AdjList {
 		passableNodes = append(passableNodes, key)
 	}
+	slices.Sort(passableNodes) // stable order of candidate sets
 
 	n := len(passableNodes)
 
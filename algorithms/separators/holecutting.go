@@ -228,7 +228,8 @@ func findObstacleComponents(g *graph.Graph) map[int]int {
 		for x := range g.Width {
 			nodeID := g.Grid[y][x]
 			if nodeID == -1 {
-				for _, dir := range graph.Directions {
+				// in octile mode diagonal obstacles touch, no diagonal move can pass between them
+				for _, dir := range g.Neighborhood() {
 					nx := x + dir[0]
 					ny := y + dir[1]
 					if nx < 0 || ny < 0 || nx >= g.Width || ny >= g.Height {
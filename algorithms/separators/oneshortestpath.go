@@ -4,7 +4,7 @@ import (
 	"bachelor-project/graph"
 	"bachelor-project/graphdecomp"
 	"context"
-	"math/rand"
+	"slices"
 	"sort"
 )
 
@@ -12,10 +12,11 @@ import (
 func OneShortestPath(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool) {
 	bordernodes := extractBoundaryNodes(g)
 	//randomize bordernodes testing order
-	rand.Shuffle(len(bordernodes), func(i, j int) {
+	shuffle(ctx, len(bordernodes), func(i, j int) {
 		bordernodes[i], bordernodes[j] = bordernodes[j], bordernodes[i]
 	})
 
+	dense := g.Dense()
 	// try every border node till one path succeeds
 	for _, node := range bordernodes {
 		select {
@@ -24,11 +25,11 @@ func OneShortestPath(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool)
 		default:
 			// proceed
 		}
-		prevMap := bfsPaths(g.AdjList, node)             // compute prev map of starting node
-		paths := createPaths(prevMap, bordernodes, node) // compute a path to every other border node
-		reducedPaths := reducePaths(g, paths)            // reduce computed paths
+		prev := bfsPaths(dense, node)                        // compute prev slice of starting node
+		paths := createPaths(dense, prev, bordernodes, node) // compute a path to every other border node
+		reducedPaths := reducePaths(g, paths)                // reduce computed paths
 		paths = nil
-		sort.Slice(reducedPaths, func(i, j int) bool { // sort every path in ascending length
+		sort.SliceStable(reducedPaths, func(i, j int) bool { // sort every path in ascending length
 			return len(reducedPaths[i]) < len(reducedPaths[j])
 		})
 		// try every path till one succeeds
@@ -50,11 +51,18 @@ func OneShortestPath(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool)
 }
 
 // Reduces given paths p to p* paths
-// Delete all subsequences of nodes with degree less than 4 except first and last node
+// Delete all subsequences of nodes with degree less than 4 (8 if octile) except first and last node
 func reducePaths(g *graph.Graph, paths map[int][]int) [][]int {
 	reducedPaths := [][]int{}
-	//iterate through all paths
-	for _, path := range paths {
+	maxDegree := g.MaxDegree()
+	//iterate through all paths, in order of their end node for a stable result
+	ends := make([]int, 0, len(paths))
+	for end := range paths {
+		ends = append(ends, end)
+	}
+	slices.Sort(ends)
+	for _, end := range ends {
+		path := paths[end]
 		if len(path) < 2 {
 			reducedPaths = append(reducedPaths, path)
 			continue
@@ -66,7 +74,7 @@ func reducePaths(g *graph.Graph, paths map[int][]int) [][]int {
 		// iterate through path
 		for i := range path {
 			degree := len(g.AdjList[path[i]])
-			if degree != 4 {
+			if degree != maxDegree {
 				// check if node is starting point of subsequence
 				if obstacleStart == -1 {
 					obstacleStart = i
@@ -85,7 +93,7 @@ func reducePaths(g *graph.Graph, paths map[int][]int) [][]int {
 					obstacleStart = -1
 					intervalEnd = -1
 				}
-				// current degree == 4, so append in path
+				// current degree == max degree, so append in path
 				reducedP = append(reducedP, path[i])
 			}
 		}
@@ -106,37 +114,46 @@ func reducePaths(g *graph.Graph, paths map[int][]int) [][]int {
 }
 
 // Returns set of paths of start node to each other boundarynode
-func createPaths(prev map[int]int, boundaryNodes []int, start int) map[int][]int {
+// prev holds dense indices of c, as computed by bfsPaths
+func createPaths(c *graph.CSR, prev []int, boundaryNodes []int, start int) map[int][]int {
 	fullPaths := make(map[int][]int)
 
 	fullPaths[start] = []int{start} // add path startnode to itself as path
+	startIdx, _ := c.Index(start)
 
 	//iterate through all boundary nodes
 	for _, node := range boundaryNodes {
+		current, exists := c.Index(node)
 		// check if boundary node was reachable of startnode (same connected component)
-		if _, exists := prev[node]; exists {
-			path := []int{node} //build path
-			current := node
-			// traverse map back to start
-			for current != start {
-				parent := prev[current]
-				path = append(path, parent)
-				current = parent
-			}
-			fullPaths[node] = path
+		if !exists || current == startIdx || prev[current] == -1 {
+			continue
+		}
+		path := []int{node} //build path
+		// traverse slice back to start
+		for current != startIdx {
+			current = prev[current]
+			path = append(path, c.NodeIDs[current])
 		}
+		fullPaths[node] = path
 	}
 
 	return fullPaths
 }
 
-// Visit each node in graph with bfs and store previous visited node in a map
-func bfsPaths(adjlist map[int][]int, start int) map[int]int {
-	prev := make(map[int]int)
-	visited := make(map[int]struct{})
-	queue := []int{start}
-
-	visited[start] = struct{}{} // set true
+// Visit each node in graph with bfs and store previous visited node (dense index) in a slice
+// unreached nodes are -1, the start node is its own previous node
+func bfsPaths(c *graph.CSR, startID int) []int {
+	prev := make([]int, c.Len())
+	for i := range prev {
+		prev[i] = -1
+	}
+	start, exists := c.Index(startID)
+	if !exists {
+		return prev
+	}
+	queue := make([]int, 0, c.Len())
+	queue = append(queue, start)
+	prev[start] = start
 	head := 0
 
 	for head < len(queue) {
@@ -144,10 +161,9 @@ func bfsPaths(adjlist map[int][]int, start int) map[int]int {
 		head++
 
 		//visit neighbors
-		for _, neighbor := range adjlist[current] {
+		for _, neighbor := range c.Adjacent(current) {
 			//put non-visited nodes into queue
-			if _, exists := visited[neighbor]; !exists {
-				visited[neighbor] = struct{}{}
+			if prev[neighbor] == -1 {
 				// set current node to previous node of neighbor
 				prev[neighbor] = current
 				queue = append(queue, neighbor)
@@ -158,14 +174,16 @@ func bfsPaths(adjlist map[int][]int, start int) map[int]int {
 	return prev
 }
 
-// returns set of boundarynodes
+// returns set of boundarynodes, sorted
 func extractBoundaryNodes(g *graph.Graph) []int {
 	boundaryNodes := []int{}
+	maxDegree := g.MaxDegree()
 
 	for node, neighbors := range g.AdjList {
-		if len(neighbors) != 4 {
+		if len(neighbors) != maxDegree {
 			boundaryNodes = append(boundaryNodes, node)
 		}
 	}
+	slices.Sort(boundaryNodes)
 	return boundaryNodes
 }
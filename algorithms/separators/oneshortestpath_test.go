@@ -41,7 +41,8 @@ func TestBfsPaths(t *testing.T) {
 		3: {1, 2},
 	}
 
-	result := bfsPaths(adjlist, 0)
+	c := graph.NewCSR(adjlist) // nodeids 0..3 equal dense indices
+	result := bfsPaths(c, 0)
 	expected := map[int]int{
 		1: 0,
 		2: 0,
@@ -56,16 +57,18 @@ func TestBfsPaths(t *testing.T) {
 }
 
 func TestCreatePaths(t *testing.T) {
-	// backtrace map from BFS
-	prevMap := map[int]int{
-		4: 3,
-		3: 1,
-		1: 0,
-	}
+	// path 0-1-3-4, node 5 is not reachable
+	c := graph.NewCSR(map[int][]int{
+		0: {1},
+		1: {0, 3},
+		3: {1, 4},
+		4: {3},
+		5: {},
+	})
 	boundary := []int{4, 0, 5}
 	start := 0
 
-	result := createPaths(prevMap, boundary, start)
+	result := createPaths(c, bfsPaths(c, start), boundary, start)
 	expected := map[int][]int{
 		4: {4, 3, 1, 0},
 		0: {0},
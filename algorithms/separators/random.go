@@ -0,0 +1,22 @@
+package separators
+
+import (
+	"context"
+	"math/rand"
+)
+
+type randKey struct{}
+
+// Returns ctx carrying the random source heuristics use for their random choices, a fixed source makes them repeatable
+func WithRand(ctx context.Context, r *rand.Rand) context.Context {
+	return context.WithValue(ctx, randKey{}, r)
+}
+
+// shuffle with the random source of ctx, the global source if ctx has none
+func shuffle(ctx context.Context, n int, swap func(i, j int)) {
+	if r, ok := ctx.Value(randKey{}).(*rand.Rand); ok {
+		r.Shuffle(n, swap)
+		return
+	}
+	rand.Shuffle(n, swap)
+}
@@ -0,0 +1,27 @@
+package separators
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestShuffleWithRand(t *testing.T) {
+	order := func(seed int64) []int {
+		ctx := WithRand(context.Background(), rand.New(rand.NewSource(seed)))
+		nodes := make([]int, 20)
+		for i := range nodes {
+			nodes[i] = i
+		}
+		shuffle(ctx, len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+		return nodes
+	}
+
+	if !reflect.DeepEqual(order(1), order(1)) {
+		t.Errorf("Expected same order for the same seed")
+	}
+	if reflect.DeepEqual(order(1), order(7)) {
+		t.Errorf("Expected other order for another seed, got %v", order(7))
+	}
+}
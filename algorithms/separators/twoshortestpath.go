@@ -4,6 +4,7 @@ import (
 	"bachelor-project/graph"
 	"bachelor-project/graphdecomp"
 	"context"
+	"slices"
 )
 
 // Decompose graph by compressing the grid and apply one shortest path.
@@ -17,11 +18,17 @@ func TwoShortestPath(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool)
 	boundaryNodesC := extractBoundaryNodes(gc)
 	// Test every boundary node of compressed grid till one offers a valid solution
 	for node := range boundaryNodesC {
-		prevMap := bfsPaths(gc.AdjList, node)
-		paths := createPaths(prevMap, boundaryNodesC, node) // compute shortest paths
+		prev := bfsPaths(gc.Dense(), node)
+		paths := createPaths(gc.Dense(), prev, boundaryNodesC, node) // compute shortest paths
+		ends := make([]int, 0, len(paths))
+		for end := range paths {
+			ends = append(ends, end)
+		}
+		slices.Sort(ends) // stable order of candidates
 		//try every path till one succeeds
 	Outerloop:
-		for _, candidate := range paths {
+		for _, end := range ends {
+			candidate := paths[end]
 			select {
 			case <-ctx.Done():
 				return nil, false
@@ -40,6 +47,13 @@ func TwoShortestPath(g *graph.Graph, ctx context.Context) ([]*graph.Graph, bool)
 						continue Outerloop
 					}
 				}
+				// the compressed grid is 4-connected with unit costs, for octile or terrain costs the sandwich argument does not hold so check convexity
+				if g.Weighted() {
+					if convexComponents, valid := graphdecomp.BalancedConvexDecomposition(g, separator, ctx); valid {
+						return convexComponents, valid
+					}
+					continue
+				}
 				if convexComponents, valid := graphdecomp.BalancedDecomposition(g, separator); valid {
 					return convexComponents, valid
 				}
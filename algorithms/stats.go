@@ -0,0 +1,264 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Shape of a built hierarchy
+type HierarchyStats struct {
+	Nodes             int              `json:"nodes"`      // nodes of the root
+	Depth             int              `json:"depth"`      // deepest level, the root is level 0
+	Components        int              `json:"components"` // hierarchy nodes including the root
+	Leaves            int              `json:"leaves"`
+	SeparatorNodes    int              `json:"separatorNodes"` // nodes that are part of a separator on any level
+	SeparatorShare    float64          `json:"separatorShare"` // separator nodes / nodes
+	UndecomposedNodes int              `json:"undecomposedNodes"`
+	Winners           map[string]int   `json:"winners"` // splits per heuristic, from the provenance
+	LeafSizes         SizeStats        `json:"leafSizes"`
+	Levels            []LevelStats     `json:"levels"`
+	Items             []ComponentStats `json:"items"` // every hierarchy node in preorder
+}
+
+// Distribution of sizes
+type SizeStats struct {
+	Count     int          `json:"count"`
+	Min       int          `json:"min"`
+	Max       int          `json:"max"`
+	Mean      float64      `json:"mean"`
+	Median    float64      `json:"median"`
+	Histogram []SizeBucket `json:"histogram"` // power of two buckets, only non-empty ones
+}
+
+// Number of sizes in [Min, Max]
+type SizeBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// All hierarchy nodes of one level
+type LevelStats struct {
+	Level      int       `json:"level"`
+	Components int       `json:"components"`
+	Splits     int       `json:"splits"`
+	Leaves     int       `json:"leaves"`
+	Branching  SizeStats `json:"branching"`  // childs per split
+	Separators SizeStats `json:"separators"` // separator size per split
+}
+
+// One hierarchy node
+type ComponentStats struct {
+	Path         []int   `json:"path"` // child indices from the root
+	Level        int     `json:"level"`
+	Nodes        int     `json:"nodes"`
+	Childs       int     `json:"childs"`
+	Separator    int     `json:"separator"`
+	Balance      float64 `json:"balance"`             // smallest / largest child, -1 for leaves
+	LargestShare float64 `json:"largestShare"`        // largest child / nodes, at most alpha for balanced splits, -1 for leaves
+	Heuristic    string  `json:"heuristic,omitempty"` // heuristic that split the node, from its provenance
+	Undecomposed []int   `json:"undecomposed,omitempty"`
+}
+
+// Compute statistics of the hierarchy of root g
+func NewHierarchyStats(g *graph.Graph) *HierarchyStats {
+	s := &HierarchyStats{Nodes: len(g.AdjList), Winners: map[string]int{}, Items: []ComponentStats{}}
+	leafSizes := []int{}
+	branching, separators := [][]int{}, [][]int{}
+
+	var walk func(component *graph.Graph, path []int)
+	walk = func(component *graph.Graph, path []int) {
+		level := len(path)
+		if level >= len(s.Levels) {
+			s.Levels = append(s.Levels, LevelStats{Level: level})
+			branching = append(branching, []int{})
+			separators = append(separators, []int{})
+		}
+		item := ComponentStats{Path: path, Level: level, Nodes: len(component.AdjList), Childs: len(component.Childs), Balance: -1, LargestShare: -1}
+		s.Components++
+		s.Depth = max(s.Depth, level)
+		s.Levels[level].Components++
+
+		if len(component.Childs) == 0 {
+			s.Leaves++
+			s.Levels[level].Leaves++
+			leafSizes = append(leafSizes, item.Nodes)
+			// the pipeline only skips graphs with less than 3 nodes, bigger leaves are left because no heuristic worked
+			if item.Nodes >= 3 {
+				item.Undecomposed = make([]int, 0, item.Nodes)
+				for node := range component.AdjList {
+					item.Undecomposed = append(item.Undecomposed, node)
+				}
+				slices.Sort(item.Undecomposed)
+				s.UndecomposedNodes += item.Nodes
+			}
+			s.Items = append(s.Items, item)
+			return
+		}
+
+		item.Separator = len(separatorOf(component))
+		if p := component.Provenance(); p != nil {
+			item.Heuristic = p.Heuristic
+			if p.Heuristic != "" {
+				s.Winners[p.Heuristic]++
+			}
+		}
+		smallest, largest := item.Nodes, 0
+		for _, child := range component.Childs {
+			smallest = min(smallest, len(child.AdjList))
+			largest = max(largest, len(child.AdjList))
+		}
+		if largest > 0 {
+			item.Balance = float64(smallest) / float64(largest)
+			item.LargestShare = float64(largest) / float64(item.Nodes)
+		}
+		s.SeparatorNodes += item.Separator
+		s.Levels[level].Splits++
+		branching[level] = append(branching[level], item.Childs)
+		separators[level] = append(separators[level], item.Separator)
+		s.Items = append(s.Items, item)
+
+		for i, child := range component.Childs {
+			walk(child, append(path[:len(path):len(path)], i))
+		}
+	}
+	walk(g, []int{})
+
+	if s.Nodes > 0 {
+		s.SeparatorShare = float64(s.SeparatorNodes) / float64(s.Nodes)
+	}
+	s.LeafSizes = newSizeStats(leafSizes)
+	for level := range s.Levels {
+		s.Levels[level].Branching = newSizeStats(branching[level])
+		s.Levels[level].Separators = newSizeStats(separators[level])
+	}
+	return s
+}
+
+func newSizeStats(sizes []int) SizeStats {
+	stats := SizeStats{Count: len(sizes), Histogram: []SizeBucket{}}
+	if len(sizes) == 0 {
+		return stats
+	}
+	sorted := slices.Clone(sizes)
+	slices.Sort(sorted)
+	stats.Min, stats.Max = sorted[0], sorted[len(sorted)-1]
+	sum := 0
+	for _, size := range sorted {
+		sum += size
+		// bucket [2^k, 2^(k+1)-1], sizes of 0 have their own bucket
+		low, high := 0, 0
+		if size > 0 {
+			low = 1
+			for low*2 <= size {
+				low *= 2
+			}
+			high = low*2 - 1
+		}
+		if n := len(stats.Histogram); n > 0 && stats.Histogram[n-1].Min == low {
+			stats.Histogram[n-1].Count++
+		} else {
+			stats.Histogram = append(stats.Histogram, SizeBucket{low, high, 1})
+		}
+	}
+	stats.Mean = float64(sum) / float64(len(sorted))
+	if mid := len(sorted) / 2; len(sorted)%2 == 1 {
+		stats.Median = float64(sorted[mid])
+	} else {
+		stats.Median = float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	return stats
+}
+
+// Write human readable report
+func (s *HierarchyStats) WriteText(w io.Writer) error {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "Nodes: %d\n", s.Nodes)
+	fmt.Fprintf(b, "Components: %d (%d leaves), depth %d\n", s.Components, s.Leaves, s.Depth)
+	fmt.Fprintf(b, "Separator nodes: %d (%.2f%% of all nodes)\n", s.SeparatorNodes, 100*s.SeparatorShare)
+	fmt.Fprintf(b, "Leaf sizes: min %d, max %d, mean %.2f, median %g\n", s.LeafSizes.Min, s.LeafSizes.Max, s.LeafSizes.Mean, s.LeafSizes.Median)
+	for _, bucket := range s.LeafSizes.Histogram {
+		fmt.Fprintf(b, "  %d-%d: %d\n", bucket.Min, bucket.Max, bucket.Count)
+	}
+
+	fmt.Fprintln(b, "Levels:")
+	for _, l := range s.Levels {
+		fmt.Fprintf(b, "  %d: %d components, %d splits, %d leaves", l.Level, l.Components, l.Splits, l.Leaves)
+		if l.Splits > 0 {
+			fmt.Fprintf(b, ", branching %d-%d (mean %.2f), separator %d-%d (mean %.2f)",
+				l.Branching.Min, l.Branching.Max, l.Branching.Mean, l.Separators.Min, l.Separators.Max, l.Separators.Mean)
+		}
+		fmt.Fprintln(b)
+	}
+
+	if len(s.Winners) > 0 {
+		fmt.Fprintln(b, "Winners:")
+		heuristics := make([]string, 0, len(s.Winners))
+		for heuristic := range s.Winners {
+			heuristics = append(heuristics, heuristic)
+		}
+		slices.Sort(heuristics)
+		for _, heuristic := range heuristics {
+			fmt.Fprintf(b, "  %s: %d splits\n", heuristic, s.Winners[heuristic])
+		}
+	}
+
+	fmt.Fprintln(b, "Splits:")
+	for _, item := range s.Items {
+		if item.Childs > 0 {
+			fmt.Fprintf(b, "  %v: %d nodes, %d childs, separator %d, balance %.3f, largest child %.3f",
+				item.Path, item.Nodes, item.Childs, item.Separator, item.Balance, item.LargestShare)
+			if item.Heuristic != "" {
+				fmt.Fprintf(b, ", by %s", item.Heuristic)
+			}
+			fmt.Fprintln(b)
+		}
+	}
+
+	fmt.Fprintf(b, "Undecomposed nodes: %d\n", s.UndecomposedNodes)
+	for _, item := range s.Items {
+		if item.Undecomposed != nil {
+			fmt.Fprintf(b, "  %v: %d nodes %v\n", item.Path, item.Nodes, item.Undecomposed)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Write report as indented JSON
+func (s *HierarchyStats) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// Write one row per hierarchy node
+func (s *HierarchyStats) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"Path", "Level", "Nodes", "Childs", "Separator", "Balance", "Largest child share", "Heuristic", "Undecomposed"})
+	for _, item := range s.Items {
+		path := make([]string, len(item.Path))
+		for i, index := range item.Path {
+			path[i] = strconv.Itoa(index)
+		}
+		writer.Write([]string{
+			strings.Join(path, "/"),
+			strconv.Itoa(item.Level),
+			strconv.Itoa(item.Nodes),
+			strconv.Itoa(item.Childs),
+			strconv.Itoa(item.Separator),
+			strconv.FormatFloat(item.Balance, 'f', 4, 64),
+			strconv.FormatFloat(item.LargestShare, 'f', 4, 64),
+			item.Heuristic,
+			strconv.FormatBool(item.Undecomposed != nil),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,110 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHierarchyStats(t *testing.T) {
+	// 0 1 2
+	// 3 4 5
+	// 6 7 8
+	// root splits into left and right column (separator 1 4 7), left column into 0 and 6 (separator 3)
+	g := graph.NewGraph(3, 3)
+	g.Grid = [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}}
+	g.BuildAdjlist()
+	induced := func(nodes ...int) *graph.Graph {
+		return &graph.Graph{AdjList: graph.InducedAdjlist(g.AdjList, nodes)}
+	}
+	left, right := induced(0, 3, 6), induced(2, 5, 8)
+	left.Childs = []*graph.Graph{induced(0), induced(6)}
+	g.Childs = []*graph.Graph{left, right}
+
+	s := NewHierarchyStats(g)
+
+	if s.Nodes != 9 || s.Components != 5 || s.Leaves != 3 || s.Depth != 2 {
+		t.Errorf("Expected 9 nodes, 5 components, 3 leaves, depth 2, got %d, %d, %d, %d", s.Nodes, s.Components, s.Leaves, s.Depth)
+	}
+	if s.SeparatorNodes != 4 || math.Abs(s.SeparatorShare-4.0/9.0) > 1e-9 {
+		t.Errorf("Expected 4 separator nodes (share 4/9), got %d (%g)", s.SeparatorNodes, s.SeparatorShare)
+	}
+	if len(s.Winners) != 0 {
+		t.Errorf("Expected no winners without provenance, got %v", s.Winners)
+	}
+	if s.UndecomposedNodes != 3 || !reflect.DeepEqual(s.Items[4].Undecomposed, []int{2, 5, 8}) {
+		t.Errorf("Expected right column as undecomposed leaf, got %d nodes, %v", s.UndecomposedNodes, s.Items[4])
+	}
+
+	expectedLeaves := SizeStats{Count: 3, Min: 1, Max: 3, Mean: 5.0 / 3.0, Median: 1, Histogram: []SizeBucket{{1, 1, 2}, {2, 3, 1}}}
+	if !reflect.DeepEqual(s.LeafSizes, expectedLeaves) {
+		t.Errorf("Expected leaf sizes %+v, got %+v", expectedLeaves, s.LeafSizes)
+	}
+
+	expectedLevels := []LevelStats{
+		{Level: 0, Components: 1, Splits: 1,
+			Branching:  SizeStats{Count: 1, Min: 2, Max: 2, Mean: 2, Median: 2, Histogram: []SizeBucket{{2, 3, 1}}},
+			Separators: SizeStats{Count: 1, Min: 3, Max: 3, Mean: 3, Median: 3, Histogram: []SizeBucket{{2, 3, 1}}}},
+		{Level: 1, Components: 2, Splits: 1, Leaves: 1,
+			Branching:  SizeStats{Count: 1, Min: 2, Max: 2, Mean: 2, Median: 2, Histogram: []SizeBucket{{2, 3, 1}}},
+			Separators: SizeStats{Count: 1, Min: 1, Max: 1, Mean: 1, Median: 1, Histogram: []SizeBucket{{1, 1, 1}}}},
+		{Level: 2, Components: 2, Leaves: 2,
+			Branching:  SizeStats{Histogram: []SizeBucket{}},
+			Separators: SizeStats{Histogram: []SizeBucket{}}},
+	}
+	if !reflect.DeepEqual(s.Levels, expectedLevels) {
+		t.Errorf("Expected levels %+v, got %+v", expectedLevels, s.Levels)
+	}
+
+	expectedItems := []ComponentStats{
+		{Path: []int{}, Level: 0, Nodes: 9, Childs: 2, Separator: 3, Balance: 1, LargestShare: 3.0 / 9.0},
+		{Path: []int{0}, Level: 1, Nodes: 3, Childs: 2, Separator: 1, Balance: 1, LargestShare: 1.0 / 3.0},
+		{Path: []int{0, 0}, Level: 2, Nodes: 1, Balance: -1, LargestShare: -1},
+		{Path: []int{0, 1}, Level: 2, Nodes: 1, Balance: -1, LargestShare: -1},
+		{Path: []int{1}, Level: 1, Nodes: 3, Balance: -1, LargestShare: -1, Undecomposed: []int{2, 5, 8}},
+	}
+	if !reflect.DeepEqual(s.Items, expectedItems) {
+		t.Errorf("Expected items %+v, got %+v", expectedItems, s.Items)
+	}
+}
+
+func TestHierarchyStatsOutput(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+	s := NewHierarchyStats(g)
+
+	winners := 0
+	for _, count := range s.Winners {
+		winners += count
+	}
+	if winners != s.Components-s.Leaves {
+		t.Errorf("Expected a winner for each of %d splits, got %v", s.Components-s.Leaves, s.Winners)
+	}
+
+	var text bytes.Buffer
+	if err := s.WriteText(&text); err != nil || !strings.Contains(text.String(), "Winners:") || !strings.Contains(text.String(), ", by ") {
+		t.Errorf("Expected text report, got %q, %v", text.String(), err)
+	}
+
+	var encoded bytes.Buffer
+	if err := s.WriteJSON(&encoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decoded := &HierarchyStats{}
+	if err := json.Unmarshal(encoded.Bytes(), decoded); err != nil || !reflect.DeepEqual(decoded, s) {
+		t.Errorf("Expected JSON round trip, got %+v, %v", decoded, err)
+	}
+
+	var table bytes.Buffer
+	if err := s.WriteCSV(&table); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows, err := csv.NewReader(&table).ReadAll()
+	if err != nil || len(rows) != s.Components+1 || len(rows[0]) != 9 || rows[1][0] != "" {
+		t.Errorf("Expected header and one row per component, got %v, %v", rows, err)
+	}
+}
@@ -0,0 +1,109 @@
+package algorithms
+
+import "bachelor-project/graph"
+
+// computes the distances between every source and every target node, table[i][j] is the distance from
+// sources[i] to targets[j] and -1 if it is not reachable. The smallest convex components of a source and its targets
+// lie on the way from the root to the source, so distances in the largest of them are exact for all targets and
+// one search per source bounded to this component answers the whole row
+func DistanceTable(g *graph.Graph, sources, targets []int) [][]float64 {
+	table := make([][]float64, len(sources))
+	for i, source := range sources {
+		table[i] = make([]float64, len(targets))
+
+		var largest *graph.Graph
+		reachable := []int{} // positions of targets in the hierarchy of source
+		for j, target := range targets {
+			component := FindSmallestConvexComponent(g, source, target)
+			if component == nil {
+				table[i][j] = -1
+				continue
+			}
+			if largest == nil || len(component.AdjList) > len(largest.AdjList) {
+				largest = component
+			}
+			reachable = append(reachable, j)
+		}
+		if largest == nil {
+			continue
+		}
+
+		ids := make([]int, len(reachable))
+		for k, j := range reachable {
+			ids[k] = targets[j]
+		}
+		distances := DistancesFrom(largest, source, ids)
+		for k, j := range reachable {
+			table[i][j] = distances[k]
+		}
+	}
+	return table
+}
+
+// computes the distances from start to every target with one search that stops when all targets are reached,
+// bfs is used for unit costs, dijkstra otherwise. -1 for targets that are not reachable
+func DistancesFrom(g *graph.Graph, startID int, targetIDs []int) []float64 {
+	distances := make([]float64, len(targetIDs))
+	for k := range distances {
+		distances[k] = -1
+	}
+	if g == nil {
+		return distances
+	}
+	c := g.Dense()
+	start, exists := c.Index(startID)
+	if !exists {
+		return distances
+	}
+
+	// dense indices of targets that still have to be reached
+	remaining := 0
+	wanted := make(map[int][]int, len(targetIDs)) // dense index -> positions in targetIDs
+	for k, id := range targetIDs {
+		if i, exists := c.Index(id); exists {
+			if len(wanted[i]) == 0 {
+				remaining++
+			}
+			wanted[i] = append(wanted[i], k)
+		}
+	}
+	reach := func(i int, cost float64) bool {
+		if positions, exists := wanted[i]; exists {
+			for _, k := range positions {
+				distances[k] = cost
+			}
+			delete(wanted, i)
+			remaining--
+		}
+		return remaining > 0
+	}
+	if remaining == 0 {
+		return distances
+	}
+
+	if g.Weighted() {
+		multiSourceDijkstra(c, []int{start}, []float64{0}, nil, reach)
+		return distances
+	}
+
+	// bfs level by level
+	depth := make([]int, c.Len())
+	for i := range depth {
+		depth[i] = -1
+	}
+	depth[start] = 0
+	queue := []int{start}
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		if !reach(current, float64(depth[current])) {
+			break
+		}
+		for _, neighbor := range c.Adjacent(current) {
+			if depth[neighbor] == -1 {
+				depth[neighbor] = depth[current] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return distances
+}
@@ -0,0 +1,50 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"testing"
+)
+
+func TestDistanceTable(t *testing.T) {
+	sources := []int{0, 13, 35, 22, 7}
+	targets := []int{5, 30, 13, 13, 27, 0, 99}
+
+	for _, metric := range []*graph.Metric{nil, {Octile: true}} {
+		g := newTestHierarchy(t, metric)
+
+		table := DistanceTable(g, sources, targets)
+		if len(table) != len(sources) {
+			t.Fatalf("Expected %d rows, got %d", len(sources), len(table))
+		}
+		for i, source := range sources {
+			for j, target := range targets {
+				expected := ShortestDistance(g, source, target)
+				if math.Abs(table[i][j]-expected) > 1e-9 {
+					t.Errorf("Distance %d -> %d is %f, expected %f", source, target, table[i][j], expected)
+				}
+			}
+		}
+	}
+}
+
+func TestDistancesFrom(t *testing.T) {
+	g := graph.NewGraph(2, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, -1, 5},
+	}
+	g.BuildAdjlist()
+
+	distances := DistancesFrom(g, 0, []int{5, 1, 4, 0, 5})
+	expected := []float64{3, 1, -1, 0, 3}
+	for k := range expected {
+		if distances[k] != expected[k] {
+			t.Errorf("Expected distances %v, got %v", expected, distances)
+			break
+		}
+	}
+	if distances := DistancesFrom(nil, 0, []int{1}); distances[0] != -1 {
+		t.Errorf("Expected -1 without component, got %v", distances)
+	}
+}
@@ -0,0 +1,120 @@
+package algorithms
+
+import (
+	"bachelor-project/graph"
+	"bachelor-project/graphdecomp"
+	"fmt"
+)
+
+// Kinds of hierarchy violations
+const (
+	ViolationPartition = "partition" // child has nodes outside of its parent or shares nodes with a sibling
+	ViolationBalance   = "balance"   // child has more than alpha * parent nodes
+	ViolationConvexity = "convexity" // distance between two child nodes is longer inside the child than in the parent
+)
+
+// Hierarchy node that breaks a guarantee of its split, with a witness
+type Violation struct {
+	Kind  string
+	Path  []int        // child indices from the root to the violating node
+	Node  *graph.Graph // violating child
+	Nodes int          // nodes of the child
+	Limit int          // balance: maximum number of nodes of a child
+
+	// witness: for convexity two child nodes and their distances (-1 in child if not connected), for partition the node in question
+	U, V           int
+	ChildDistance  float64
+	ParentDistance float64
+}
+
+func (v Violation) String() string {
+	switch v.Kind {
+	case ViolationBalance:
+		return fmt.Sprintf("%s at %v: child has %d nodes, limit is %d", v.Kind, v.Path, v.Nodes, v.Limit)
+	case ViolationConvexity:
+		return fmt.Sprintf("%s at %v: d(%d, %d) is %g in child but %g in parent", v.Kind, v.Path, v.U, v.V, v.ChildDistance, v.ParentDistance)
+	default:
+		return fmt.Sprintf("%s at %v: node %d", v.Kind, v.Path, v.U)
+	}
+}
+
+// Checks every split of the hierarchy of root g: childs are disjoint parts of their parent, have at most
+// alpha * parent nodes and are convex in their parent. Childs that are a connected component of their parent don't have
+// to be balanced. Unlike the heuristics no shortcut like observation 7 is used,
+// convexity is checked with graphdecomp.FindConvexityWitness.
+// Returns one violation per broken guarantee of a child
+func ValidateHierarchy(g *graph.Graph, alpha float64) []Violation {
+	violations := []Violation{}
+	var walk func(parent *graph.Graph, path []int)
+	walk = func(parent *graph.Graph, path []int) {
+		limit := int(float64(len(parent.AdjList)) * alpha)
+		owner := map[int]int{}
+		for i, child := range parent.Childs {
+			childPath := append(path[:len(path):len(path)], i)
+			base := Violation{Path: childPath, Node: child, Nodes: len(child.AdjList), Limit: limit}
+
+			if node, ok := partitionWitness(parent, child, i, owner); !ok {
+				v := base
+				v.Kind, v.U = ViolationPartition, node
+				violations = append(violations, v)
+				continue // distances are meaningless for nodes outside of the parent
+			}
+			if len(child.AdjList) > limit && !isComponent(parent, child) {
+				v := base
+				v.Kind = ViolationBalance
+				violations = append(violations, v)
+			}
+			nodes := make([]int, 0, len(child.AdjList))
+			for node := range child.AdjList {
+				nodes = append(nodes, node)
+			}
+			// every node is part of parent, checked by partitionWitness
+			if witness, _ := graphdecomp.FindConvexityWitness(parent, nodes); witness != nil {
+				v := base
+				v.Kind, v.U, v.V = ViolationConvexity, witness.U, witness.V
+				v.ChildDistance, v.ParentDistance = witness.SubsetDistance, witness.GraphDistance
+				violations = append(violations, v)
+			}
+			walk(child, childPath)
+		}
+	}
+	walk(g, []int{})
+	return violations
+}
+
+// Returns a node of child i that is not part of parent or already owned by a sibling
+func partitionWitness(parent, child *graph.Graph, i int, owner map[int]int) (int, bool) {
+	for node := range child.AdjList {
+		if _, exists := parent.AdjList[node]; !exists {
+			return node, false
+		}
+		if _, exists := owner[node]; exists {
+			return node, false
+		}
+		owner[node] = i
+	}
+	return -1, true
+}
+
+// Returns true if no node of child has a neighbor in parent outside of child
+func isComponent(parent, child *graph.Graph) bool {
+	for node, neighbors := range child.AdjList {
+		if len(neighbors) < len(parent.AdjList[node]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports every violation of the hierarchy of g, t is a *testing.T or *testing.B
+func CheckHierarchyValid(t interface {
+	Helper()
+	Errorf(format string, args ...any)
+}, g *graph.Graph, alpha float64) bool {
+	t.Helper()
+	violations := ValidateHierarchy(g, alpha)
+	for _, v := range violations {
+		t.Errorf("Invalid hierarchy: %v", v)
+	}
+	return len(violations) == 0
+}
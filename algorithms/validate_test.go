@@ -0,0 +1,102 @@
+package algorithms
+
+import (
+	"bachelor-project/config"
+	"bachelor-project/graph"
+	"reflect"
+	"testing"
+)
+
+func TestValidateHierarchy(t *testing.T) {
+	g := newTestHierarchy(t, &graph.Metric{Octile: true})
+	if len(g.Childs) == 0 {
+		t.Fatalf("Expected a split of the root")
+	}
+	CheckHierarchyValid(t, g, config.Alpha)
+}
+
+func TestValidateHierarchyViolations(t *testing.T) {
+	// 3x3 grid, 4-connected
+	// 0 1 2
+	// 3 4 5
+	// 6 7 8
+	g := graph.NewGraph(3, 3)
+	g.Grid = [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}}
+	g.BuildAdjlist()
+	induced := func(nodes ...int) *graph.Graph {
+		return &graph.Graph{AdjList: graph.InducedAdjlist(g.AdjList, nodes)}
+	}
+
+	testCases := []struct {
+		name     string
+		childs   []*graph.Graph
+		expected []Violation
+	}{
+		{
+			name:     "Valid split",
+			childs:   []*graph.Graph{induced(0, 3, 6), induced(2, 5, 8)},
+			expected: []Violation{},
+		},
+		{
+			// 0 -> 2 takes 6 steps around the bottom instead of 2
+			name:   "U shape",
+			childs: []*graph.Graph{induced(0, 3, 6, 7, 8, 5, 2)},
+			expected: []Violation{
+				{Kind: ViolationBalance, Path: []int{0}, Nodes: 7, Limit: 6},
+				{Kind: ViolationConvexity, Path: []int{0}, Nodes: 7, Limit: 6},
+			},
+		},
+		{
+			name:   "Disconnected",
+			childs: []*graph.Graph{induced(0, 2)},
+			expected: []Violation{
+				{Kind: ViolationConvexity, Path: []int{0}, Nodes: 2, Limit: 6},
+			},
+		},
+		{
+			name:   "Overlapping childs",
+			childs: []*graph.Graph{induced(0, 1), induced(1, 2)},
+			expected: []Violation{
+				{Kind: ViolationPartition, Path: []int{1}, Nodes: 2, Limit: 6, U: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g.Childs = tc.childs
+			violations := ValidateHierarchy(g, 2.0/3.0)
+			if len(violations) != len(tc.expected) {
+				t.Fatalf("Expected %d violations, got %v", len(tc.expected), violations)
+			}
+			for i, v := range violations {
+				expected := tc.expected[i]
+				expected.Node = tc.childs[expected.Path[0]]
+				if v.Kind == ViolationConvexity {
+					// any pair with a shorter distance in the parent is a witness
+					dChild, dParent := ShortestDistance(v.Node, v.U, v.V), ShortestDistance(g, v.U, v.V)
+					if dChild != v.ChildDistance || dParent != v.ParentDistance || dChild != -1 && dChild <= dParent {
+						t.Errorf("Witness does not show a violation (distance %g in child, %g in parent): %v", dChild, dParent, v)
+					}
+					expected.U, expected.V = v.U, v.V
+					expected.ChildDistance, expected.ParentDistance = v.ChildDistance, v.ParentDistance
+				}
+				if !reflect.DeepEqual(v, expected) {
+					t.Errorf("Expected violation %v, got %v", expected, v)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHierarchyComponents(t *testing.T) {
+	// connected components don't have to be balanced
+	g := graph.NewGraph(1, 5)
+	g.Grid = [][]int{{0, 1, 2, -1, 4}}
+	g.BuildAdjlist()
+	BuildConvexHierarchy(g)
+	if len(g.Childs) != 2 {
+		t.Fatalf("Expected split into 2 components, got %d childs", len(g.Childs))
+	}
+	CheckHierarchyValid(t, g, 2.0/3.0)
+}
@@ -5,12 +5,15 @@ import (
 	"bachelor-project/algorithms/separators"
 	"bachelor-project/config"
 	"bachelor-project/graph"
-	"bufio"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -64,6 +67,7 @@ func CombinedBenchmarkConvexNormal(mapDir, scenDir, outputBasePath string) {
 		start2 := time.Now()
 		g := graph.LoadGraphFromFile(mapPath)
 		algorithms.BuildConvexHierarchy(g)
+		labels := algorithms.NewHierarchyLabels(g)
 		time2 := time.Since(start2).Milliseconds()
 
 		countSubgraphs := countLeaves(g)
@@ -97,7 +101,7 @@ func CombinedBenchmarkConvexNormal(mapDir, scenDir, outputBasePath string) {
 			continue
 		}
 		writer := csv.NewWriter(distFile)
-		writer.Write([]string{"Instance", "Bucket", "Time1 normal (ms)", "Time2 convex (ms)", "Distance 1", "Distance 2", "search size normal", "search size convex", "Time to find subgraph Convex", "Time to find Distance in subgraph Convex", "Count subgraphs"})
+		writer.Write([]string{"Instance", "Bucket", "Time1 normal (ms)", "Time2 convex (ms)", "Distance 1", "Distance 2", "search size normal", "search size convex", "Time to find subgraph Convex", "Time to find Distance in subgraph Convex", "Count subgraphs", "Path valid", "Time3 A* normal (ms)", "Time4 A* convex (ms)", "Distance A*", "Time5 bidirectional normal (ms)", "Time6 bidirectional convex (ms)", "Distance bidirectional", "Time7 separator convex (ms)", "Distance separator", "Time to find subgraph Convex (ns)", "Time to find subgraph Labels (ns)"})
 		writer.Flush()
 
 		for i, s := range scenarios {
@@ -109,13 +113,13 @@ func CombinedBenchmarkConvexNormal(mapDir, scenDir, outputBasePath string) {
 			y := graph.NodeID(goalX, goalY, mapWidth)
 
 			startTimeNormal := time.Now()
-			distanceNormal := algorithms.BreadthFirstSearch(g.AdjList, x, y)
+			distanceNormal := algorithms.ShortestDistance(g, x, y)
 			runTimeNormal := time.Since(startTimeNormal).Milliseconds()
 			searchSpaceSizeNormal := len(g.AdjList)
 
 			startTimeConvex := time.Now()
 			subgraph := algorithms.FindSmallestConvexComponent(g, x, y)
-			distanceConvex := algorithms.BreadthFirstSearch(subgraph.AdjList, x, y)
+			distanceConvex := algorithms.ShortestDistance(subgraph, x, y)
 			runTimeConvex := time.Since(startTimeConvex).Milliseconds()
 
 			startTimeConvexFindSubgraph := time.Now()
@@ -123,23 +127,66 @@ func CombinedBenchmarkConvexNormal(mapDir, scenDir, outputBasePath string) {
 			runTimeConvexFindSubgraph := time.Since(startTimeConvexFindSubgraph).Milliseconds()
 
 			startTimeConvexFindDistance := time.Now()
-			algorithms.BreadthFirstSearch(subgraph.AdjList, x, y)
+			algorithms.ShortestDistance(subgraph, x, y)
 			runTimeConvexFindDistance := time.Since(startTimeConvexFindDistance).Milliseconds()
 
 			searchSpaceSizeConvex := len(subgraph.AdjList)
 
+			// goal directed search on the map and in the convex component
+			startTimeAStarNormal := time.Now()
+			algorithms.AStar(g, x, y)
+			runTimeAStarNormal := time.Since(startTimeAStarNormal).Milliseconds()
+
+			startTimeAStarConvex := time.Now()
+			distanceAStar := algorithms.AStar(algorithms.FindSmallestConvexComponent(g, x, y), x, y)
+			runTimeAStarConvex := time.Since(startTimeAStarConvex).Milliseconds()
+
+			// search from both ends on the map and in the convex component
+			startTimeBidirectionalNormal := time.Now()
+			algorithms.BidirectionalDistance(g, x, y)
+			runTimeBidirectionalNormal := time.Since(startTimeBidirectionalNormal).Milliseconds()
+
+			startTimeBidirectionalConvex := time.Now()
+			distanceBidirectional := algorithms.BidirectionalDistance(algorithms.FindSmallestConvexComponent(g, x, y), x, y)
+			runTimeBidirectionalConvex := time.Since(startTimeBidirectionalConvex).Milliseconds()
+
+			// search through the separator of the smallest convex component
+			startTimeSeparator := time.Now()
+			distanceSeparator := algorithms.SeparatorDistance(g, x, y)
+			runTimeSeparator := time.Since(startTimeSeparator).Milliseconds()
+
+			// component lookup by probing adjacency lists vs by labels
+			startTimeFindSubgraph := time.Now()
+			algorithms.FindSmallestConvexComponent(g, x, y)
+			runTimeFindSubgraphNs := time.Since(startTimeFindSubgraph).Nanoseconds()
+
+			startTimeFindSubgraphLabels := time.Now()
+			labels.SmallestComponent(x, y)
+			runTimeFindSubgraphLabelsNs := time.Since(startTimeFindSubgraphLabels).Nanoseconds()
+
 			writer.Write([]string{
 				fmt.Sprintf("Instance-%d", i+1),
 				fmt.Sprintf("%d", bucket),
 				fmt.Sprintf("%d", runTimeNormal),
 				fmt.Sprintf("%d", runTimeConvex),
-				fmt.Sprintf("%d", distanceNormal),
-				fmt.Sprintf("%d", distanceConvex),
+				fmt.Sprintf("%g", distanceNormal),
+				fmt.Sprintf("%g", distanceConvex),
 				fmt.Sprintf("%d", searchSpaceSizeNormal),
 				fmt.Sprintf("%d", searchSpaceSizeConvex),
 				fmt.Sprintf("%d", runTimeConvexFindSubgraph),
 				fmt.Sprintf("%d", runTimeConvexFindDistance),
 				fmt.Sprintf("%d", countSubgraphs),
+				fmt.Sprintf("%t", pathValid(g, subgraph, x, y, distanceConvex)),
+				fmt.Sprintf("%d", runTimeAStarNormal),
+				fmt.Sprintf("%d", runTimeAStarConvex),
+				fmt.Sprintf("%g", distanceAStar),
+				fmt.Sprintf("%d", runTimeBidirectionalNormal),
+				fmt.Sprintf("%d", runTimeBidirectionalConvex),
+				fmt.Sprintf("%g", distanceBidirectional),
+				fmt.Sprintf("%d", runTimeSeparator),
+				fmt.Sprintf("%g", distanceSeparator),
+				fmt.Sprintf("%d", runTimeFindSubgraphNs),
+				fmt.Sprintf("%d", runTimeFindSubgraphLabelsNs),
 			})
 			writer.Flush()
 		}
@@ -178,7 +225,9 @@ func BuildGraphBenchmarkConvexNormal(directory string, csvFilePath string) {
 	defer writer.Flush()
 
 	// write header
-	writer.Write([]string{"Instance", "Time1 normal (ms)", "Time2 convex (ms)", "Number of Subgraphs"})
+	workers := runtime.GOMAXPROCS(0)
+	writer.Write([]string{"Instance", "Time1 normal (ms)", "Time2 convex (ms)", "Number of Subgraphs",
+		fmt.Sprintf("Time3 convex parallel %d workers (ms)", workers), "Speedup", "Same shape"})
 
 	// iterate over maps
 	for _, mapPath := range mapFiles {
@@ -198,16 +247,111 @@ func BuildGraphBenchmarkConvexNormal(directory string, csvFilePath string) {
 
 		countSubgraphs := countLeaves(g) // of convex building
 
+		// parallel convex building
+		start3 := time.Now()
+		parallel := graph.LoadGraphFromFile(mapPath)
+		algorithms.BuildConvexHierarchyParallel(parallel, workers)
+		time3 := time.Since(start3).Milliseconds()
+		speedup := float64(time2) / float64(max(time3, 1))
+
 		writer.Write([]string{
 			mapName,
 			fmt.Sprintf("%d", time1),
 			fmt.Sprintf("%d", time2),
 			fmt.Sprintf("%d", countSubgraphs),
+			fmt.Sprintf("%d", time3),
+			fmt.Sprintf("%.2f", speedup),
+			fmt.Sprintf("%t", algorithms.SameShape(g, parallel)),
 		})
 	}
 	fmt.Println("Benchmarking completed. Results saved to:", csvFilePath)
 }
 
+// Memory of the hierarchy with adjacency lists per node vs the compact hierarchy sharing the root CSR,
+// estimated from the data structures and measured as live heap after garbage collection
+func HierarchyMemoryCompactNormal(directory string, csvFilePath string) {
+	// search for all .map files in folder
+	mapFiles, err := filepath.Glob(filepath.Join(directory, "*.map"))
+	if err != nil {
+		fmt.Printf("Error reading map directory: %v\n", err)
+		return
+	}
+
+	// create csv folder
+	err = os.MkdirAll(filepath.Dir(csvFilePath), os.ModePerm)
+	if err != nil {
+		fmt.Printf("Error creating CSV folder: %v\n", err)
+		return
+	}
+
+	// create csv file
+	csvFile, err := os.Create(csvFilePath + ".csv")
+	if err != nil {
+		fmt.Printf("Error creating CSV file: %v\n", err)
+		return
+	}
+	defer csvFile.Close()
+
+	writer := csv.NewWriter(csvFile)
+	defer writer.Flush()
+
+	// write header
+	writer.Write([]string{"Instance", "Number of Nodes", "Number of Subgraphs", "Estimated normal (bytes)", "Estimated compact (bytes)",
+		"Estimated saved (%)", "Heap normal (bytes)", "Heap compact (bytes)", "Heap saved (%)"})
+
+	for _, mapPath := range mapFiles {
+		mapName := strings.TrimSuffix(filepath.Base(mapPath), ".map")
+		fmt.Printf("Processing map: %s\n", mapName)
+
+		base := liveHeap()
+		g := graph.LoadGraphFromFile(mapPath)
+		if g == nil {
+			continue
+		}
+		algorithms.BuildConvexHierarchy(g)
+		nodes := len(g.AdjList)
+		countSubgraphs := countLeaves(g)
+		estimatedNormal := graph.HierarchyBytes(g)
+		heapNormal := liveHeap() - base
+		runtime.KeepAlive(g)
+
+		// g is not used anymore, only the compact hierarchy stays alive
+		h := graph.NewCompactHierarchy(g)
+		estimatedCompact := h.CSR.Bytes() + h.Bytes()
+		heapCompact := liveHeap() - base
+		runtime.KeepAlive(h)
+
+		writer.Write([]string{
+			mapName,
+			fmt.Sprintf("%d", nodes),
+			fmt.Sprintf("%d", countSubgraphs),
+			fmt.Sprintf("%d", estimatedNormal),
+			fmt.Sprintf("%d", estimatedCompact),
+			fmt.Sprintf("%.1f", savedPercent(estimatedNormal, estimatedCompact)),
+			fmt.Sprintf("%d", heapNormal),
+			fmt.Sprintf("%d", heapCompact),
+			fmt.Sprintf("%.1f", savedPercent(heapNormal, heapCompact)),
+		})
+	}
+	fmt.Println("Memory benchmark completed. Results saved to:", csvFilePath)
+}
+
+// live heap in bytes after a garbage collection
+func liveHeap() int {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int(stats.HeapAlloc)
+}
+
+// share of normal saved by compact in percent
+func savedPercent(normal, compact int) float64 {
+	if normal <= 0 {
+		return 0
+	}
+	return 100 * float64(normal-compact) / float64(normal)
+}
+
 // Distance normal/convex, Time normal/convex, Time finding convex subgraph, time bfs in convex subgraph, count subgraphs
 func FindDistanceTimeNormalConvex(mapDir, scenDir, csvFilePath string) {
 	mapFiles, err := filepath.Glob(filepath.Join(mapDir, "*.map"))
@@ -236,7 +380,7 @@ func FindDistanceTimeNormalConvex(mapDir, scenDir, csvFilePath string) {
 
 		writer := csv.NewWriter(csvFile)
 		// write Header
-		writer.Write([]string{"Instance", "Bucket", "Time1 normal (ms)", "Time2 convex (ms)", "Distance 1", "Distance 2", "search size normal", "search size convex", "Time to find subgraph Convex", "Time to find Distance in subgraph Convex", "Count subgraphs"})
+		writer.Write([]string{"Instance", "Bucket", "Time1 normal (ms)", "Time2 convex (ms)", "Distance 1", "Distance 2", "search size normal", "search size convex", "Time to find subgraph Convex", "Time to find Distance in subgraph Convex", "Count subgraphs", "Path valid", "Time3 A* normal (ms)", "Time4 A* convex (ms)", "Distance A*", "Time5 bidirectional normal (ms)", "Time6 bidirectional convex (ms)", "Distance bidirectional", "Time7 separator convex (ms)", "Distance separator", "Time to find subgraph Convex (ns)", "Time to find subgraph Labels (ns)"})
 
 		// Check scen file
 		if _, err := os.Stat(scenPath); os.IsNotExist(err) {
@@ -252,6 +396,7 @@ func FindDistanceTimeNormalConvex(mapDir, scenDir, csvFilePath string) {
 
 		g := graph.LoadGraphFromFile(mapPath)
 		algorithms.BuildConvexHierarchy(g)
+		labels := algorithms.NewHierarchyLabels(g)
 
 		for i, s := range scenarios {
 			mapWidth := s[0]
@@ -262,13 +407,13 @@ func FindDistanceTimeNormalConvex(mapDir, scenDir, csvFilePath string) {
 			y := graph.NodeID(goalX, goalY, mapWidth)
 
 			startTimeNormal := time.Now()
-			distanceNormal := algorithms.BreadthFirstSearch(g.AdjList, x, y)
+			distanceNormal := algorithms.ShortestDistance(g, x, y)
 			runTimeNormal := time.Since(startTimeNormal).Milliseconds()
 			searchSpaceSizeNormal := len(g.AdjList)
 
 			startTimeConvex := time.Now()
 			subgraph := algorithms.FindSmallestConvexComponent(g, x, y)
-			distanceConvex := algorithms.BreadthFirstSearch(subgraph.AdjList, x, y)
+			distanceConvex := algorithms.ShortestDistance(subgraph, x, y)
 			runTimeConvex := time.Since(startTimeConvex).Milliseconds()
 
 			startTimeConvexFindSubgraph := time.Now()
@@ -276,10 +421,42 @@ func FindDistanceTimeNormalConvex(mapDir, scenDir, csvFilePath string) {
 			runTimeConvexFindSubgraph := time.Since(startTimeConvexFindSubgraph).Milliseconds()
 
 			startTimeConvexFindDistance := time.Now()
-			algorithms.BreadthFirstSearch(subgraph.AdjList, x, y)
+			algorithms.ShortestDistance(subgraph, x, y)
 			runTimeConvexFindDistance := time.Since(startTimeConvexFindDistance).Milliseconds()
 
 			searchSpaceSizeConvex := len(subgraph.AdjList)
+
+			// goal directed search on the map and in the convex component
+			startTimeAStarNormal := time.Now()
+			algorithms.AStar(g, x, y)
+			runTimeAStarNormal := time.Since(startTimeAStarNormal).Milliseconds()
+
+			startTimeAStarConvex := time.Now()
+			distanceAStar := algorithms.AStar(algorithms.FindSmallestConvexComponent(g, x, y), x, y)
+			runTimeAStarConvex := time.Since(startTimeAStarConvex).Milliseconds()
+
+			// search from both ends on the map and in the convex component
+			startTimeBidirectionalNormal := time.Now()
+			algorithms.BidirectionalDistance(g, x, y)
+			runTimeBidirectionalNormal := time.Since(startTimeBidirectionalNormal).Milliseconds()
+
+			startTimeBidirectionalConvex := time.Now()
+			distanceBidirectional := algorithms.BidirectionalDistance(algorithms.FindSmallestConvexComponent(g, x, y), x, y)
+			runTimeBidirectionalConvex := time.Since(startTimeBidirectionalConvex).Milliseconds()
+
+			// search through the separator of the smallest convex component
+			startTimeSeparator := time.Now()
+			distanceSeparator := algorithms.SeparatorDistance(g, x, y)
+			runTimeSeparator := time.Since(startTimeSeparator).Milliseconds()
+
+			// component lookup by probing adjacency lists vs by labels
+			startTimeFindSubgraph := time.Now()
+			algorithms.FindSmallestConvexComponent(g, x, y)
+			runTimeFindSubgraphNs := time.Since(startTimeFindSubgraph).Nanoseconds()
+
+			startTimeFindSubgraphLabels := time.Now()
+			labels.SmallestComponent(x, y)
+			runTimeFindSubgraphLabelsNs := time.Since(startTimeFindSubgraphLabels).Nanoseconds()
 			countSubgraphs := countLeaves(g)
 
 			// write into csv file
@@ -288,13 +465,24 @@ func FindDistanceTimeNormalConvex(mapDir, scenDir, csvFilePath string) {
 				fmt.Sprintf("%d", bucket),
 				fmt.Sprintf("%d", runTimeNormal),
 				fmt.Sprintf("%d", runTimeConvex), // find subgraph + distance
-				fmt.Sprintf("%d", distanceNormal),
-				fmt.Sprintf("%d", distanceConvex),
+				fmt.Sprintf("%g", distanceNormal),
+				fmt.Sprintf("%g", distanceConvex),
 				fmt.Sprintf("%d", searchSpaceSizeNormal),
 				fmt.Sprintf("%d", searchSpaceSizeConvex),
 				fmt.Sprintf("%d", runTimeConvexFindSubgraph),
 				fmt.Sprintf("%d", runTimeConvexFindDistance),
 				fmt.Sprintf("%d", countSubgraphs),
+				fmt.Sprintf("%t", pathValid(g, subgraph, x, y, distanceConvex)),
+				fmt.Sprintf("%d", runTimeAStarNormal),
+				fmt.Sprintf("%d", runTimeAStarConvex),
+				fmt.Sprintf("%g", distanceAStar),
+				fmt.Sprintf("%d", runTimeBidirectionalNormal),
+				fmt.Sprintf("%d", runTimeBidirectionalConvex),
+				fmt.Sprintf("%g", distanceBidirectional),
+				fmt.Sprintf("%d", runTimeSeparator),
+				fmt.Sprintf("%g", distanceSeparator),
+				fmt.Sprintf("%d", runTimeFindSubgraphNs),
+				fmt.Sprintf("%d", runTimeFindSubgraphLabelsNs),
 			})
 		}
 		writer.Flush()
@@ -304,6 +492,89 @@ func FindDistanceTimeNormalConvex(mapDir, scenDir, csvFilePath string) {
 	fmt.Println("Benchmarking completed. Results saved to:", csvFilePath)
 }
 
+// Queries per second of the query engine for different worker counts, all scenarios of a map are one batch
+func QueryThroughput(mapDir, scenDir, csvFilePath string) {
+	mapFiles, err := filepath.Glob(filepath.Join(mapDir, "*.map"))
+	if err != nil {
+		fmt.Printf("Error reading map directory: %v\n", err)
+		return
+	}
+
+	// create csv folder
+	err = os.MkdirAll(filepath.Dir(csvFilePath), os.ModePerm)
+	if err != nil {
+		fmt.Printf("Error creating CSV folder: %v\n", err)
+		return
+	}
+
+	// create csv file
+	csvFile, err := os.Create(csvFilePath + ".csv")
+	if err != nil {
+		fmt.Printf("Error creating CSV file: %v\n", err)
+		return
+	}
+	defer csvFile.Close()
+
+	writer := csv.NewWriter(csvFile)
+	defer writer.Flush()
+
+	// write header
+	writer.Write([]string{"Instance", "Workers", "Queries", "Time (ms)", "Queries per second", "Speedup"})
+
+	// 1, 2, 4, ... up to the number of CPUs
+	workerCounts := []int{}
+	for workers := 1; workers < runtime.GOMAXPROCS(0); workers *= 2 {
+		workerCounts = append(workerCounts, workers)
+	}
+	workerCounts = append(workerCounts, runtime.GOMAXPROCS(0))
+
+	for _, mapPath := range mapFiles {
+		mapName := strings.TrimSuffix(filepath.Base(mapPath), ".map")
+		scenPath := filepath.Join(scenDir, mapName+".map.scen")
+		fmt.Printf("Processing map: %s\n", mapName)
+
+		scenarios := LoadScenario(scenPath)
+		if len(scenarios) == 0 {
+			fmt.Printf("No valid scenarios in: %s\n", scenPath)
+			continue
+		}
+		g := graph.LoadGraphFromFile(mapPath)
+		if g == nil {
+			continue
+		}
+		algorithms.BuildConvexHierarchy(g)
+
+		queries := make([]algorithms.Query, len(scenarios))
+		for i, s := range scenarios {
+			queries[i] = algorithms.Query{Start: graph.NodeID(s[1], s[2], s[0]), End: graph.NodeID(s[3], s[4], s[0])}
+		}
+
+		var sequential time.Duration
+		for _, workers := range workerCounts {
+			engine := algorithms.NewQueryEngine(g, workers)
+			engine.Distances(queries) // warm up CSR caches and buffers
+
+			start := time.Now()
+			engine.Distances(queries)
+			elapsed := time.Since(start)
+			if workers == 1 {
+				sequential = elapsed
+			}
+
+			writer.Write([]string{
+				mapName,
+				fmt.Sprintf("%d", workers),
+				fmt.Sprintf("%d", len(queries)),
+				fmt.Sprintf("%d", elapsed.Milliseconds()),
+				fmt.Sprintf("%.0f", float64(len(queries))/elapsed.Seconds()),
+				fmt.Sprintf("%.2f", sequential.Seconds()/elapsed.Seconds()),
+			})
+		}
+		writer.Flush()
+	}
+	fmt.Println("Throughput benchmark completed. Results saved to:", csvFilePath)
+}
+
 func GetSizeOfGraph(directory string, csvFilePath string) {
 	// find all .map in folder
 	mapFiles, err := filepath.Glob(filepath.Join(directory, "*.map"))
@@ -468,38 +739,122 @@ func BenchEveryHeuristic(directory string, csvFilePath string) {
 	fmt.Println("Graph size analysis completed. Output saved to:", csvFilePath)
 }
 
+// Load scenario file, prints the error and returns nil if the file is not valid, see LoadScenarioFile
 func LoadScenario(filePath string) [][6]int {
-	file, err := os.Open(filePath)
+	scen, err := LoadScenarioFile(filePath)
 	if err != nil {
-		fmt.Printf("Error: could not open file: %v\n", err)
+		fmt.Printf("Error: could not load scenario: %v\n", err)
 		return nil
 	}
+	return scen
+}
+
+// Open and parse a MovingAI scenario file
+func LoadScenarioFile(filePath string) ([][6]int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return ParseScenario(file, filePath)
+}
 
-	if !scanner.Scan() {
-		return nil
+// Parse a MovingAI scenario, name is used for error messages
+// returns per scenario: map width, start x, start y, goal x, goal y, bucket
+func ParseScenario(r io.Reader, name string) ([][6]int, error) {
+	lr := graph.NewLineReader(r)
+	fail := func(column int, format string, args ...any) error {
+		return &graph.ParseError{File: name, Line: lr.Line, Column: column, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	line, err := lr.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, &graph.ParseError{File: name, Line: 1, Msg: `missing "version" line`}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fields := strings.Fields(line); len(fields) != 2 || fields[0] != "version" {
+		return nil, fail(1, "expected version header, got %q", line)
 	}
 
 	var scen [][6]int
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Fields(line)
-
-		mapWidth, _ := strconv.Atoi(parts[2])
-		// mapHeight, _ := strconv.Atoi(parts[3])
-		startX, _ := strconv.Atoi(parts[4])
-		startY, _ := strconv.Atoi(parts[5])
-		goalX, _ := strconv.Atoi(parts[6])
-		goalY, _ := strconv.Atoi(parts[7])
-		bucket, _ := strconv.Atoi(parts[0])
+	for {
+		line, err := lr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts, columns := fieldsWithColumns(line)
+		if len(parts) == 0 {
+			continue
+		}
+		// bucket, map, map width, map height, start x, start y, goal x, goal y, optimal length
+		if len(parts) != 9 {
+			return nil, fail(0, "expected 9 fields, got %d", len(parts))
+		}
+
+		values := [7]int{}
+		for k, field := range []int{0, 2, 3, 4, 5, 6, 7} {
+			value, err := strconv.Atoi(parts[field])
+			if err != nil || value < 0 {
+				return nil, fail(columns[field], "expected non-negative integer, got %q", parts[field])
+			}
+			values[k] = value
+		}
+		bucket, mapWidth, mapHeight := values[0], values[1], values[2]
+		startX, startY, goalX, goalY := values[3], values[4], values[5], values[6]
+
+		// coordinates must lie inside of the map
+		for k, coord := range []int{startX, startY, goalX, goalY} {
+			limit := mapWidth
+			if k%2 == 1 {
+				limit = mapHeight
+			}
+			if coord >= limit {
+				return nil, fail(columns[4+k], "coordinate %d outside of %dx%d map", coord, mapWidth, mapHeight)
+			}
+		}
+		if _, err := strconv.ParseFloat(parts[8], 64); err != nil {
+			return nil, fail(columns[8], "expected optimal length, got %q", parts[8])
+		}
 
 		coords := [6]int{mapWidth, startX, startY, goalX, goalY, bucket}
 		scen = append(scen, coords)
 	}
 
-	return scen
+	return scen, nil
+}
+
+// splits line at white space and returns the fields with their column (starting at 1)
+func fieldsWithColumns(line string) ([]string, []int) {
+	fields := []string{}
+	columns := []int{}
+	start := -1
+	for i := 0; i <= len(line); i++ {
+		space := i == len(line) || line[i] == ' ' || line[i] == '\t'
+		if space && start != -1 {
+			fields = append(fields, line[start:i])
+			columns = append(columns, start+1)
+			start = -1
+		} else if !space && start == -1 {
+			start = i
+		}
+	}
+	return fields, columns
+}
+
+// Checks that the path found in the subgraph is a path of g from x to y with the reported distance
+func pathValid(g, subgraph *graph.Graph, x, y int, distance float64) bool {
+	path, _ := algorithms.ShortestPath(subgraph, x, y)
+	if path == nil {
+		return distance == -1
+	}
+	length, ok := algorithms.PathLength(g, path)
+	return ok && path[0] == x && path[len(path)-1] == y && math.Abs(length-distance) < 1e-9
 }
 
 // Count number of subgraphs
@@ -0,0 +1,95 @@
+package benchmark
+
+import (
+	"bachelor-project/graph"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScenario(t *testing.T) {
+	input := "version 1\r\n0\tm.map\t4\t3\t0\t0\t3\t2\t3.41421356\r\n\r\n1\tm.map\t4\t3\t1\t2\t2\t0\t2.41421356\r\n"
+	scen, err := ParseScenario(strings.NewReader(input), "test.scen")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := [][6]int{{4, 0, 0, 3, 2, 0}, {4, 1, 2, 2, 0, 1}}
+	if !reflect.DeepEqual(scen, expected) {
+		t.Errorf("Expected scenarios %v, got %v", expected, scen)
+	}
+}
+
+func TestParseScenarioErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		line   int
+		column int
+	}{
+		{
+			name:   "Empty file",
+			input:  "",
+			line:   1,
+			column: 0,
+		},
+		{
+			name:   "Bad version line",
+			input:  "version\n0\tm.map\t4\t3\t0\t0\t3\t2\t3.4\n",
+			line:   1,
+			column: 1,
+		},
+		{
+			name:   "Short row",
+			input:  "version 1\n0\tm.map\t4\t3\t0\t0\t3\t2\n",
+			line:   2,
+			column: 0,
+		},
+		{
+			name:   "Non-numeric start",
+			input:  "version 1\n0\tm.map\t4\t3\tx\t0\t3\t2\t3.4\n",
+			line:   2,
+			column: 13,
+		},
+		{
+			name:   "Non-numeric optimal length",
+			input:  "version 1\n0 m.map 4 3 0 0 3 2 long\n",
+			line:   2,
+			column: 21,
+		},
+		{
+			name:   "Goal outside of map",
+			input:  "version 1\n0 m.map 4 3 0 0 3 3 3.4\n",
+			line:   2,
+			column: 19,
+		},
+		{
+			name:   "Truncated file",
+			input:  "version 1\n0\tm.map\t4\t3\t0\t0\t3\t2\t3.4\n1\tm.map\t4\t3\t1",
+			line:   3,
+			column: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scen, err := ParseScenario(strings.NewReader(tc.input), "test.scen")
+			if scen != nil {
+				t.Errorf("Expected no scenarios on error, got %v", scen)
+			}
+			var parseErr *graph.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected ParseError, got %v", err)
+			}
+			if parseErr.File != "test.scen" || parseErr.Line != tc.line || parseErr.Column != tc.column {
+				t.Errorf("Expected position test.scen:%d:%d, got %v", tc.line, tc.column, parseErr)
+			}
+		})
+	}
+}
+
+func TestLoadScenarioFile(t *testing.T) {
+	if _, err := LoadScenarioFile("does_not_exist.scen"); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
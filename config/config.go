@@ -5,3 +5,29 @@ import "time"
 var Alpha float64 = 2.0 / 3.0
 var KaFFPaPath = "KaHIP/build/kaffpa" // relative path from project folder
 var Time time.Duration = 60 * time.Second
+
+// Names of the separator heuristics the pipeline tries in order, see algorithms.RegisterSeparator.
+// Built in: kaffpa (needs KaFFPaPath), osp, tsp, rowcol, holecutting, guesscheck
+var Pipeline = []string{"osp", "tsp", "rowcol", "holecutting"}
+
+// How the pipeline picks a decomposition: "sequential" tries the heuristics in order,
+// "portfolio" runs all at once and takes the first valid one,
+// "best" runs all at once and takes the valid one with the lowest score (see algorithms.SetSplitScore)
+var PipelineMode = "sequential"
+
+// Time budget of all heuristics of one node in best mode, heuristics still running afterwards are stopped
+var SelectionTime time.Duration = 60 * time.Second
+
+// Seed of the random choices of heuristics, builds with the same seed give the same hierarchy
+var Seed int64 = 1
+
+// Time limit per heuristic name, heuristics without entry use Time
+var HeuristicTime = map[string]time.Duration{}
+var Octile = false // 8-connected movement with diagonal cost sqrt(2), otherwise 4-connected
+
+// Cost of entering a cell per MovingAI terrain character, characters without entry are not passable.
+// Edges cost the mean of both cells times the step length (1 or sqrt(2))
+var TerrainCosts = map[byte]float64{
+	'.': 1, // passable terrain
+	'G': 1, // passable terrain
+}
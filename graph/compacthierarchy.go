@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"slices"
+	"unsafe"
+)
+
+// Hierarchy in which all components share the CSR of the root graph.
+// Nodes are ordered such that every component is a contiguous range of Order:
+// the ranges of its childs one after another, followed by its separator nodes.
+type CompactHierarchy struct {
+	CSR        *CSR // adjacency of the root graph with its edge costs
+	Metric     *Metric
+	Order      []int // position -> dense index of CSR
+	Pos        []int // dense index of CSR -> position in Order
+	Components []CompactComponent
+}
+
+// Component of a compact hierarchy, index 0 is the root and childs come after their parent (preorder)
+type CompactComponent struct {
+	Start     int // members are Order[Start:End]
+	End       int
+	Separator int // Order[Separator:End] belong to no child (all members for leaves)
+	Parent    int // -1 for the root
+	Childs    []int
+}
+
+// Convert built hierarchy of root g, the Childs tree of g is not modified and can be dropped afterwards
+func NewCompactHierarchy(g *Graph) *CompactHierarchy {
+	c := g.Dense()
+	h := &CompactHierarchy{
+		CSR:    c,
+		Metric: g.Metric,
+		Order:  make([]int, 0, c.Len()),
+		Pos:    make([]int, c.Len()),
+	}
+	for i := range h.Pos {
+		h.Pos[i] = -1
+	}
+	h.place(g, -1)
+	return h
+}
+
+// append childs of hierarchy node g and then its remaining nodes to Order, returns index of component
+func (h *CompactHierarchy) place(g *Graph, parent int) int {
+	index := len(h.Components)
+	h.Components = append(h.Components, CompactComponent{Start: len(h.Order), Parent: parent})
+
+	childs := make([]int, 0, len(g.Childs))
+	for _, child := range g.Childs {
+		childs = append(childs, h.place(child, index))
+	}
+
+	separator := len(h.Order)
+	for node := range g.AdjList {
+		if i, exists := h.CSR.Index(node); exists && h.Pos[i] == -1 {
+			h.Pos[i] = 0 // placed, position is set below
+			h.Order = append(h.Order, i)
+		}
+	}
+	slices.Sort(h.Order[separator:]) // independent of map iteration order
+	for p := separator; p < len(h.Order); p++ {
+		h.Pos[h.Order[p]] = p
+	}
+
+	component := &h.Components[index]
+	component.Separator = separator
+	component.End = len(h.Order)
+	if len(childs) > 0 {
+		component.Childs = childs
+	}
+	return index
+}
+
+// Number of components
+func (h *CompactHierarchy) Len() int {
+	return len(h.Components)
+}
+
+// Returns number of nodes of component
+func (h *CompactHierarchy) Size(component int) int {
+	return h.Components[component].End - h.Components[component].Start
+}
+
+// Returns true if nodeid is a member of component
+func (h *CompactHierarchy) Contains(component, id int) bool {
+	i, exists := h.CSR.Index(id)
+	if !exists {
+		return false
+	}
+	c := h.Components[component]
+	return c.Start <= h.Pos[i] && h.Pos[i] < c.End
+}
+
+// Returns nodeids of component in ascending order
+func (h *CompactHierarchy) Members(component int) []int {
+	c := h.Components[component]
+	ids := make([]int, 0, c.End-c.Start)
+	for _, i := range h.Order[c.Start:c.End] {
+		ids = append(ids, h.CSR.NodeIDs[i])
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Returns smallest component that contains start and end node, -1 if one of them is not part of the graph
+func (h *CompactHierarchy) SmallestComponent(startID, endID int) int {
+	start, startExists := h.CSR.Index(startID)
+	end, endExists := h.CSR.Index(endID)
+	if !(startExists && endExists) {
+		return -1
+	}
+	ps, pe := h.Pos[start], h.Pos[end]
+
+	component := 0
+	for {
+		next := -1
+		for _, child := range h.Components[component].Childs {
+			c := h.Components[child]
+			if c.Start <= ps && ps < c.End && c.Start <= pe && pe < c.End {
+				next = child
+				break
+			}
+		}
+		if next == -1 {
+			return component
+		}
+		component = next
+	}
+}
+
+// Estimated memory of the compact hierarchy in bytes (without the shared CSR)
+func (h *CompactHierarchy) Bytes() int {
+	bytes := int(unsafe.Sizeof(*h)) + (len(h.Order)+len(h.Pos))*int(unsafe.Sizeof(0))
+	bytes += len(h.Components) * int(unsafe.Sizeof(CompactComponent{}))
+	for _, c := range h.Components {
+		bytes += len(c.Childs) * int(unsafe.Sizeof(0))
+	}
+	return bytes
+}
+
+// Estimated memory of the CSR in bytes
+func (c *CSR) Bytes() int {
+	word := int(unsafe.Sizeof(0))
+	bytes := int(unsafe.Sizeof(*c)) + (len(c.Offsets)+len(c.Neighbors)+len(c.NodeIDs))*word
+	bytes += len(c.Weights) * int(unsafe.Sizeof(0.0))
+	return bytes + len(c.index)*(2*word+mapOverhead)
+}
+
+// estimated bucket overhead of a map entry in bytes
+const mapOverhead = 16
+
+// Estimated memory of the adjacency lists, grids and cached lookup structures (CSR, split index)
+// of g and all its descendants in bytes
+func HierarchyBytes(g *Graph) int {
+	word := int(unsafe.Sizeof(0))
+	bytes := int(unsafe.Sizeof(*g))
+	if c := g.dense.Load(); c != nil {
+		bytes += c.Bytes()
+	}
+	if s := g.splitIndex.Load(); s != nil {
+		bytes += int(unsafe.Sizeof(*s)) + (len(s.Owner)+len(s.Separator))*word
+		for _, row := range s.Distances {
+			bytes += int(unsafe.Sizeof(row)) + len(row)*int(unsafe.Sizeof(0.0))
+		}
+	}
+	for _, neighbors := range g.AdjList {
+		bytes += word + int(unsafe.Sizeof(neighbors)) + mapOverhead + cap(neighbors)*word
+	}
+	for _, row := range g.Grid {
+		bytes += int(unsafe.Sizeof(row)) + cap(row)*word
+	}
+	for _, child := range g.Childs {
+		bytes += HierarchyBytes(child)
+	}
+	return bytes
+}
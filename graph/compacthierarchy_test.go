@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewCompactHierarchy(t *testing.T) {
+	g := testHierarchy()
+	h := NewCompactHierarchy(g)
+
+	if h.Len() != 5 {
+		t.Fatalf("Expected 5 components, got %d", h.Len())
+	}
+	// preorder: root, left, right, right/top, right/bottom
+	expectedMembers := [][]int{
+		{0, 1, 2, 3, 5, 6, 7, 8},
+		{0, 3, 6},
+		{2, 5, 8},
+		{2},
+		{8},
+	}
+	expectedParents := []int{-1, 0, 0, 2, 2}
+	for i, members := range expectedMembers {
+		if got := h.Members(i); !reflect.DeepEqual(got, members) {
+			t.Errorf("Component %d: expected members %v, got %v", i, members, got)
+		}
+		if h.Size(i) != len(members) {
+			t.Errorf("Component %d: expected size %d, got %d", i, len(members), h.Size(i))
+		}
+		if h.Components[i].Parent != expectedParents[i] {
+			t.Errorf("Component %d: expected parent %d, got %d", i, expectedParents[i], h.Components[i].Parent)
+		}
+	}
+
+	// separators are at the end of their component
+	root := h.Components[0]
+	separator := []int{}
+	for _, i := range h.Order[root.Separator:root.End] {
+		separator = append(separator, h.CSR.NodeIDs[i])
+	}
+	if !reflect.DeepEqual(separator, g.Separator) {
+		t.Errorf("Expected root separator %v, got %v", g.Separator, separator)
+	}
+
+	tests := []struct {
+		start, end, expected int
+	}{
+		{0, 6, 1},
+		{2, 8, 2},
+		{2, 2, 3},
+		{0, 8, 0},
+		{1, 1, 0},
+		{0, 4, -1}, // obstacle
+	}
+	for _, tt := range tests {
+		if got := h.SmallestComponent(tt.start, tt.end); got != tt.expected {
+			t.Errorf("SmallestComponent(%d, %d) = %d, expected %d", tt.start, tt.end, got, tt.expected)
+		}
+	}
+	if !h.Contains(2, 5) || h.Contains(1, 5) || h.Contains(0, 4) {
+		t.Errorf("Unexpected component membership")
+	}
+}
+
+func TestHierarchyBytes(t *testing.T) {
+	g := testHierarchy()
+	before := HierarchyBytes(g)
+
+	// cached CSRs are part of the hierarchy
+	c := g.Childs[0].Dense()
+	if after := HierarchyBytes(g); after != before+c.Bytes() {
+		t.Errorf("Expected %d bytes with cached CSR of a child, got %d", before+c.Bytes(), after)
+	}
+	g.Childs[0].ResetDense()
+	if after := HierarchyBytes(g); after != before {
+		t.Errorf("Expected %d bytes after dropping the CSR, got %d", before, after)
+	}
+}
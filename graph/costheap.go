@@ -0,0 +1,21 @@
+package graph
+
+// Entry of a dijkstra priority queue, Node is usually a dense index of a CSR
+type CostItem struct {
+	Node int
+	Cost float64
+}
+
+// Min heap of CostItems, implements heap.Interface
+type CostHeap []CostItem
+
+func (h CostHeap) Len() int           { return len(h) }
+func (h CostHeap) Less(i, j int) bool { return h[i].Cost < h[j].Cost }
+func (h CostHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *CostHeap) Push(x any)        { *h = append(*h, x.(CostItem)) }
+func (h *CostHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,21 @@
+package graph
+
+import (
+	"container/heap"
+	"testing"
+)
+
+func TestCostHeap(t *testing.T) {
+	h := &CostHeap{}
+	for node, cost := range []float64{3, 1, 2.5, 0, 1} {
+		heap.Push(h, CostItem{Node: node, Cost: cost})
+	}
+	previous := -1.0
+	for h.Len() > 0 {
+		item := heap.Pop(h).(CostItem)
+		if item.Cost < previous {
+			t.Errorf("Expected items in order of cost, got %v after %v", item.Cost, previous)
+		}
+		previous = item.Cost
+	}
+}
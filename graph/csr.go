@@ -0,0 +1,101 @@
+package graph
+
+import "slices"
+
+// CSR is a compact array based form of an adjacency list (compressed sparse row).
+// Nodes are renumbered to a contiguous index 0..Len()-1 in ascending NodeID order,
+// the neighbors of index i are Neighbors[Offsets[i]:Offsets[i+1]] (also dense indices).
+type CSR struct {
+	Offsets   []int
+	Neighbors []int
+	Weights   []float64   // edge costs parallel to Neighbors, nil for unit costs
+	NodeIDs   []int       // dense index -> original nodeid
+	MinCost   float64     // cheapest terrain cost of the nodes (set by Graph.Dense), 1 without terrain costs
+	index     map[int]int // original nodeid -> dense index
+}
+
+// Build CSR from an adjacency list, the neighbor order of every node is kept
+func NewCSR(adjlist map[int][]int) *CSR {
+	ids := make([]int, 0, len(adjlist))
+	edges := 0
+	for id, neighbors := range adjlist {
+		ids = append(ids, id)
+		edges += len(neighbors)
+	}
+	slices.Sort(ids) // stable numbering independent of map iteration order
+
+	c := &CSR{
+		Offsets:   make([]int, len(ids)+1),
+		Neighbors: make([]int, 0, edges),
+		NodeIDs:   ids,
+		MinCost:   1,
+		index:     make(map[int]int, len(ids)),
+	}
+	for i, id := range ids {
+		c.index[id] = i
+	}
+	for i, id := range ids {
+		for _, neighbor := range adjlist[id] {
+			// neighbors without an own entry are not part of the graph
+			if j, exists := c.index[neighbor]; exists {
+				c.Neighbors = append(c.Neighbors, j)
+			}
+		}
+		c.Offsets[i+1] = len(c.Neighbors)
+	}
+	return c
+}
+
+// Build CSR with edge costs from an adjacency list, cost is called with original nodeids
+func NewWeightedCSR(adjlist map[int][]int, cost func(v, w int) float64) *CSR {
+	c := NewCSR(adjlist)
+	c.Weights = make([]float64, len(c.Neighbors))
+	for i, id := range c.NodeIDs {
+		for k := c.Offsets[i]; k < c.Offsets[i+1]; k++ {
+			c.Weights[k] = cost(id, c.NodeIDs[c.Neighbors[k]])
+		}
+	}
+	return c
+}
+
+// Number of nodes
+func (c *CSR) Len() int {
+	return len(c.NodeIDs)
+}
+
+// Returns dense index of a nodeid and whether the node exists
+func (c *CSR) Index(id int) (int, bool) {
+	i, exists := c.index[id]
+	return i, exists
+}
+
+// Returns neighbors (dense indices) of dense index i
+func (c *CSR) Adjacent(i int) []int {
+	return c.Neighbors[c.Offsets[i]:c.Offsets[i+1]]
+}
+
+// Returns edge costs of dense index i, parallel to Adjacent(i), nil for unit costs
+func (c *CSR) AdjacentWeights(i int) []float64 {
+	if c.Weights == nil {
+		return nil
+	}
+	return c.Weights[c.Offsets[i]:c.Offsets[i+1]]
+}
+
+// Returns degree of dense index i
+func (c *CSR) Degree(i int) int {
+	return c.Offsets[i+1] - c.Offsets[i]
+}
+
+// Compatibility accessor, rebuilds the map based adjacency list with original nodeids
+func (c *CSR) AdjList() map[int][]int {
+	adjlist := make(map[int][]int, c.Len())
+	for i, id := range c.NodeIDs {
+		neighbors := make([]int, 0, c.Degree(i))
+		for _, j := range c.Adjacent(i) {
+			neighbors = append(neighbors, c.NodeIDs[j])
+		}
+		adjlist[id] = neighbors
+	}
+	return adjlist
+}
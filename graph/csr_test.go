@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewCSR(t *testing.T) {
+	adjList := map[int][]int{
+		7: {3},
+		3: {7, 5},
+		5: {3},
+		9: {},
+	}
+	c := NewCSR(adjList)
+
+	if c.Len() != 4 {
+		t.Fatalf("Expected 4 nodes, got %d", c.Len())
+	}
+	// dense indices follow ascending nodeids
+	if !reflect.DeepEqual(c.NodeIDs, []int{3, 5, 7, 9}) {
+		t.Errorf("Expected sorted nodeids, got %v", c.NodeIDs)
+	}
+	i, exists := c.Index(3)
+	if !exists || i != 0 {
+		t.Errorf("Expected index 0 for node 3, got %d (%v)", i, exists)
+	}
+	if _, exists := c.Index(4); exists {
+		t.Errorf("Node 4 should not exist")
+	}
+	// neighbor order is kept: 3 -> 7, 5
+	if !reflect.DeepEqual(c.Adjacent(0), []int{2, 1}) {
+		t.Errorf("Expected neighbors [2 1] for node 3, got %v", c.Adjacent(0))
+	}
+	if c.Degree(3) != 0 {
+		t.Errorf("Expected degree 0 for node 9, got %d", c.Degree(3))
+	}
+	if !reflect.DeepEqual(c.AdjList(), adjList) {
+		t.Errorf("AdjList does not match original.\nExpected: %v\nGot: %v", adjList, c.AdjList())
+	}
+}
+
+func TestDense(t *testing.T) {
+	g := NewGraph(3, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, -1, 5},
+		{-1, 7, -1},
+	}
+	g.BuildAdjlist()
+
+	c := g.Dense()
+	if c != g.Dense() {
+		t.Errorf("Dense should be cached")
+	}
+	if c.Len() != len(g.AdjList) {
+		t.Errorf("Expected %d nodes, got %d", len(g.AdjList), c.Len())
+	}
+
+	g.AddEdge(7, 5)
+	if g.Dense() == c {
+		t.Errorf("AddEdge should reset the cached CSR")
+	}
+}
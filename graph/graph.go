@@ -1,12 +1,10 @@
 package graph
 
 import (
-	"bufio"
 	"fmt"
-	"os"
+	"math"
 	"slices"
-	"strconv"
-	"strings"
+	"sync/atomic"
 )
 
 // For visiting neighbors
@@ -17,20 +15,54 @@ var Directions = [4][2]int{
 	{0, 1},  // east
 }
 
+// For visiting neighbors in octile mode, cardinal directions first then diagonals
+var OctileDirections = [8][2]int{
+	{-1, 0},  // west
+	{1, 0},   // east
+	{0, -1},  // north
+	{0, 1},   // south
+	{-1, -1}, // north west
+	{1, -1},  // north east
+	{-1, 1},  // south west
+	{1, 1},   // south east
+}
+
+// Movement model shared by a graph and all of its subgraphs
+// a nil metric is the 4-connected grid with unit costs
+type Metric struct {
+	Octile bool      // 8-connected, diagonal moves cost sqrt(2) and may not cut corners
+	Cost   []float64 // terrain cost per nodeid, nil if every cell costs 1
+}
+
 type Graph struct {
 	AdjList map[int][]int // adjacency list
 	Grid    [][]int
 	Childs  []*Graph
 	Height  int
 	Width   int
+	Metric  *Metric
+
+	// Nodes of AdjList that belong to no child, sorted, nil if the graph was not split
+	Separator []int
+
+	// Position of Grid[0][0] in the root grid and width of the root grid,
+	// nodeids of every subgraph are computed with the root width
+	OffsetX   int
+	OffsetY   int
+	RootWidth int
+
+	dense      atomic.Pointer[CSR]        // cached CSR form of AdjList, see Dense()
+	provenance *Provenance                // how the split was found, see Provenance()
+	splitIndex atomic.Pointer[SplitIndex] // cached lookup tables of separator queries, see SplitIndex()
 }
 
-// Create new graph object
+// Create new graph object, as root of its own grid
 func NewGraph(height int, width int) *Graph {
 	return &Graph{
-		AdjList: make(map[int][]int), // creates empty adjlist
-		Height:  height,
-		Width:   width,
+		AdjList:   make(map[int][]int), // creates empty adjlist
+		Height:    height,
+		Width:     width,
+		RootWidth: width,
 	}
 }
 
@@ -40,6 +72,80 @@ func (g *Graph) AddEdge(v, w int) {
 		g.AdjList[v] = make([]int, 0, 4)
 	}
 	g.AdjList[v] = append(g.AdjList[v], w) // add w to the adjacency list of v
+	g.ResetDense()
+}
+
+// Returns the CSR form of the adjacency list, it is built on first use and cached.
+// Call ResetDense after modifying AdjList directly.
+func (g *Graph) Dense() *CSR {
+	if c := g.dense.Load(); c != nil {
+		return c
+	}
+	var c *CSR
+	if g.Weighted() {
+		c = NewWeightedCSR(g.AdjList, g.EdgeCost)
+		if g.Metric.Cost != nil && c.Len() > 0 {
+			c.MinCost = math.Inf(1)
+			for _, id := range c.NodeIDs {
+				c.MinCost = min(c.MinCost, g.Metric.Cost[id])
+			}
+		}
+	} else {
+		c = NewCSR(g.AdjList)
+	}
+	g.dense.Store(c)
+	return c
+}
+
+// Drops the cached CSR form, the next call of Dense rebuilds it
+func (g *Graph) ResetDense() {
+	g.dense.Store(nil)
+}
+
+// Returns true if graph is 8-connected
+func (g *Graph) Octile() bool {
+	return g.Metric != nil && g.Metric.Octile
+}
+
+// Returns true if edges don't have unit costs, distances then need dijkstra instead of bfs
+func (g *Graph) Weighted() bool {
+	return g.Metric != nil && (g.Metric.Octile || g.Metric.Cost != nil)
+}
+
+// Returns degree of a node without any blocked neighbor
+func (g *Graph) MaxDegree() int {
+	if g.Octile() {
+		return 8
+	}
+	return 4
+}
+
+// Returns directions to visit neighbors in the grid
+func (g *Graph) Neighborhood() [][2]int {
+	if g.Octile() {
+		return OctileDirections[:]
+	}
+	return Directions[:]
+}
+
+// Returns cost of edge v-w, the step length (sqrt(2) for diagonals otherwise 1)
+// times the mean terrain cost of v and w
+func (g *Graph) EdgeCost(v, w int) float64 {
+	if g.Metric == nil {
+		return 1
+	}
+	step := 1.0
+	if g.Metric.Octile {
+		xv, yv := g.Coordinates(v)
+		xw, yw := g.Coordinates(w)
+		if xv != xw && yv != yw {
+			step = math.Sqrt2
+		}
+	}
+	if g.Metric.Cost != nil {
+		return step * (g.Metric.Cost[v] + g.Metric.Cost[w]) / 2
+	}
+	return step
 }
 
 // removes a node and all its incident edges from the graph (through adjacecy list)
@@ -83,72 +189,50 @@ func CoordinatesFromNodeID(id, width int) (x, y int) {
 	return x, y
 }
 
-// Load graph from file and build grid and adjacency list
-func LoadGraphFromFile(filePath string) *Graph {
-	file, err := os.Open(filePath)
-	if err != nil {
-		fmt.Printf("Error: could not open file: %v\n", err)
-		return nil
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-
-	if !scanner.Scan() {
-		return nil
+// Returns width of the root grid, graphs built without NewGraph count as root
+func (g *Graph) rootWidth() int {
+	if g.RootWidth > 0 {
+		return g.RootWidth
 	}
-	scanner.Scan()
+	return g.Width
+}
 
-	heightLine := scanner.Text()
-	parts := strings.Fields(heightLine)
-	height, err := strconv.Atoi(parts[1])
-	if err != nil {
-		return nil
-	}
+// Converts local grid cell (Grid[y][x]) to global coordinates of the root grid
+func (g *Graph) GlobalCoordinates(x, y int) (int, int) {
+	return x + g.OffsetX, y + g.OffsetY
+}
 
-	// read third line width
-	if !scanner.Scan() {
-		return nil
-	}
-	widthLine := scanner.Text()
-	parts = strings.Fields(widthLine)
-	width, err := strconv.Atoi(parts[1])
-	if err != nil {
-		return nil
-	}
+// Converts global coordinates to a local grid cell, ok is false if the cell is outside of Grid
+func (g *Graph) LocalCoordinates(globalX, globalY int) (x, y int, ok bool) {
+	x, y = globalX-g.OffsetX, globalY-g.OffsetY
+	ok = x >= 0 && y >= 0 && x < g.Width && y < g.Height
+	return x, y, ok
+}
 
-	// check for word map
-	if !scanner.Scan() {
-		return nil
-	}
-	if scanner.Text() != "map" {
-		return nil
-	}
+// Returns nodeid of local grid cell, also if the cell is not passable
+func (g *Graph) NodeIDAt(x, y int) int {
+	globalX, globalY := g.GlobalCoordinates(x, y)
+	return NodeID(globalX, globalY, g.rootWidth())
+}
 
-	// read grid from fifth line
-	grid := make([][]int, height)
-	for y := 0; y < height && scanner.Scan(); y++ {
-		line := scanner.Text()
+// Returns global coordinates of a nodeid
+func (g *Graph) Coordinates(id int) (x, y int) {
+	return CoordinatesFromNodeID(id, g.rootWidth())
+}
 
-		grid[y] = make([]int, width)
+// Returns local grid cell of a nodeid, ok is false if the cell is outside of Grid
+func (g *Graph) LocalFromNodeID(id int) (x, y int, ok bool) {
+	return g.LocalCoordinates(g.Coordinates(id))
+}
 
-		for x := range width {
-			// ".", "G" are passable nodes
-			if line[x] == '.' || line[x] == 'G' {
-				grid[y][x] = NodeID(x, y, width)
-			} else {
-				grid[y][x] = -1
-			}
-		}
+// Load graph from file and build grid and adjacency list
+// prints the error and returns nil if the file is not a valid map, see LoadMap
+func LoadGraphFromFile(filePath string) *Graph {
+	graph, err := LoadMap(filePath)
+	if err != nil {
+		fmt.Printf("Error: could not load map: %v\n", err)
+		return nil
 	}
-
-	//create graph object
-	graph := NewGraph(height, width)
-
-	graph.Grid = grid
-
-	graph.BuildAdjlist()
-
 	return graph
 }
 
@@ -160,24 +244,79 @@ func (g *Graph) BuildAdjlist() {
 			if g.Grid[y][x] == -1 {
 				continue
 			}
-			g.AdjList[g.Grid[y][x]] = []int{}
-			// check neighbors
-			for _, dir := range Directions {
-				nx := x + dir[0]
-				ny := y + dir[1]
-				// check for out of grid nodes
-				if nx < 0 || ny < 0 || nx >= g.Width || ny >= g.Height {
+			g.AdjList[g.Grid[y][x]] = g.adjacentCells(x, y)
+		}
+	}
+	g.ResetDense()
+}
+
+// Returns nodeids of passable neighbors of local grid cell (x,y)
+func (g *Graph) adjacentCells(x, y int) []int {
+	neighbors := make([]int, 0, g.MaxDegree())
+	for _, dir := range g.Neighborhood() {
+		nx := x + dir[0]
+		ny := y + dir[1]
+		// check for out of grid nodes
+		if nx < 0 || ny < 0 || nx >= g.Width || ny >= g.Height {
+			continue
+		}
+		// check for passable neighbors
+		if g.Grid[ny][nx] == -1 {
+			continue
+		}
+		// diagonal move must not cut a corner, both orthogonal nodes have to be passable
+		if dir[0] != 0 && dir[1] != 0 && (g.Grid[y][nx] == -1 || g.Grid[ny][x] == -1) {
+			continue
+		}
+		//add edge
+		neighbors = append(neighbors, g.Grid[ny][nx])
+	}
+	return neighbors
+}
+
+// Toggles passability of local grid cells and updates the adjacency lists of the cells and their neighbors.
+// Cells outside of the grid are ignored, opened cells without terrain cost get cost 1.
+// Returns nodeids of opened and closed cells and of all passable cells whose adjacency list changed
+func (g *Graph) ToggleCells(cells [][2]int) (opened, closed, changed []int) {
+	for _, cell := range cells {
+		x, y := cell[0], cell[1]
+		if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+			continue
+		}
+		if g.Grid[y][x] == -1 {
+			g.Grid[y][x] = g.NodeIDAt(x, y)
+			opened = append(opened, g.Grid[y][x])
+			if g.Metric != nil && g.Metric.Cost != nil && g.Metric.Cost[g.Grid[y][x]] == 0 {
+				g.Metric.Cost[g.Grid[y][x]] = 1
+			}
+		} else {
+			closed = append(closed, g.Grid[y][x])
+			delete(g.AdjList, g.Grid[y][x])
+			g.Grid[y][x] = -1
+		}
+	}
+
+	// rebuild adjacency of every passable cell in the 3x3 block of a toggled cell,
+	// in octile mode a toggled cell also decides about diagonals between its neighbors
+	seen := make(map[int]struct{})
+	for _, cell := range cells {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				x, y := cell[0]+dx, cell[1]+dy
+				if x < 0 || y < 0 || x >= g.Width || y >= g.Height || g.Grid[y][x] == -1 {
 					continue
 				}
-				// check for passable neighbors
-				if g.Grid[ny][nx] == -1 {
+				if _, exists := seen[g.Grid[y][x]]; exists {
 					continue
 				}
-				//add edge
-				g.AddEdge(g.Grid[y][x], g.Grid[ny][nx])
+				seen[g.Grid[y][x]] = struct{}{}
+				g.AdjList[g.Grid[y][x]] = g.adjacentCells(x, y)
+				changed = append(changed, g.Grid[y][x])
 			}
 		}
 	}
+	g.ResetDense()
+	return opened, closed, changed
 }
 
 // Copy adjacency list of a given graph object and return the copy
@@ -188,3 +327,22 @@ func (g *Graph) CopyAdjlist() map[int][]int {
 	}
 	return copy
 }
+
+// Returns adjacency list of the subgraph induced by nodes
+func InducedAdjlist(adjlist map[int][]int, nodes []int) map[int][]int {
+	set := make(map[int]struct{}, len(nodes))
+	for _, node := range nodes {
+		set[node] = struct{}{}
+	}
+	induced := make(map[int][]int, len(nodes))
+	for _, node := range nodes {
+		neighbors := make([]int, 0, len(adjlist[node]))
+		for _, neighbor := range adjlist[node] {
+			if _, exists := set[neighbor]; exists {
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+		induced[node] = neighbors
+	}
+	return induced
+}
@@ -1,7 +1,10 @@
 package graph
 
 import (
+	"bachelor-project/config"
+	"math"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -170,3 +173,135 @@ func TestLoadGraphFromFile(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildAdjlistOctile(t *testing.T) {
+	g := NewGraph(3, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, -1, 5},
+		{-1, 7, 8},
+	}
+	g.Metric = &Metric{Octile: true}
+	g.BuildAdjlist()
+
+	// diagonals are only added if no corner is cut
+	expected := map[int][]int{
+		0: {1, 3},
+		1: {0, 2},
+		2: {1, 5},
+		3: {0},
+		5: {2, 8},
+		7: {8},
+		8: {7, 5},
+	}
+	for k, v := range expected {
+		if !reflect.DeepEqual(g.AdjList[k], v) {
+			t.Errorf("BuildAdjlist failed at node %d: expected %v, got %v", k, v, g.AdjList[k])
+		}
+	}
+
+	g.Grid[1][1] = 4
+	g.AdjList = map[int][]int{}
+	g.BuildAdjlist()
+	if len(g.AdjList[4]) != 7 {
+		t.Errorf("Expected 7 neighbors of node 4, got %v", g.AdjList[4])
+	}
+	if g.EdgeCost(4, 0) != math.Sqrt2 || g.EdgeCost(4, 1) != 1 {
+		t.Errorf("Expected diagonal cost sqrt(2) and straight cost 1")
+	}
+}
+
+func TestLoadGraphFromFileTerrain(t *testing.T) {
+	original := config.TerrainCosts
+	config.TerrainCosts = map[byte]float64{'.': 1, 'G': 1, 'S': 3}
+	defer func() { config.TerrainCosts = original }()
+
+	graph := LoadGraphFromFile("graph_terrain_test.txt")
+	if graph == nil {
+		t.Fatal("LoadGraphFromFile returned nil")
+	}
+
+	// swamp is passable, trees are not listed and stay obstacles
+	expectedGrid := [][]int{
+		{0, 1, 2},
+		{3, -1, -1},
+		{6, 7, 8},
+	}
+	if !reflect.DeepEqual(graph.Grid, expectedGrid) {
+		t.Errorf("Grid does not match expected grid.\nExpected: %v\nGot: %v", expectedGrid, graph.Grid)
+	}
+	if !graph.Weighted() {
+		t.Fatal("Expected weighted graph for terrain costs")
+	}
+	if graph.EdgeCost(0, 1) != 2 || graph.EdgeCost(0, 3) != 1 {
+		t.Errorf("Expected edge costs 2 and 1, got %f and %f", graph.EdgeCost(0, 1), graph.EdgeCost(0, 3))
+	}
+	if c := graph.Dense(); c.MinCost != 1 {
+		t.Errorf("Expected cheapest terrain cost 1, got %f", c.MinCost)
+	}
+	// subgraph of the swamp cell only
+	swamp := &Graph{AdjList: map[int][]int{1: {}}, Metric: graph.Metric}
+	if c := swamp.Dense(); c.MinCost != 3 {
+		t.Errorf("Expected cheapest terrain cost 3 of the swamp, got %f", c.MinCost)
+	}
+}
+
+func TestGraphCoordinates(t *testing.T) {
+	// 2x2 section at (3,1) of a grid with width 6
+	g := NewGraph(2, 2)
+	g.OffsetX, g.OffsetY, g.RootWidth = 3, 1, 6
+	g.Grid = [][]int{
+		{9, 10},
+		{15, -1},
+	}
+
+	if id := g.NodeIDAt(0, 1); id != 15 {
+		t.Errorf("Expected nodeid 15, got %d", id)
+	}
+	if x, y := g.GlobalCoordinates(1, 0); x != 4 || y != 1 {
+		t.Errorf("Expected global (4,1), got (%d,%d)", x, y)
+	}
+	if x, y := g.Coordinates(10); x != 4 || y != 1 {
+		t.Errorf("Expected global (4,1) for node 10, got (%d,%d)", x, y)
+	}
+	if x, y, ok := g.LocalFromNodeID(10); !ok || x != 1 || y != 0 {
+		t.Errorf("Expected local (1,0) for node 10, got (%d,%d) %v", x, y, ok)
+	}
+	if _, _, ok := g.LocalFromNodeID(0); ok {
+		t.Errorf("Node 0 is outside of the subgraph")
+	}
+}
+
+func TestToggleCells(t *testing.T) {
+	g := NewGraph(2, 3)
+	g.Grid = [][]int{
+		{0, -1, 2},
+		{3, 4, 5},
+	}
+	g.BuildAdjlist()
+
+	opened, closed, changed := g.ToggleCells([][2]int{{1, 0}, {1, 1}, {5, 5}})
+	if !reflect.DeepEqual(opened, []int{1}) || !reflect.DeepEqual(closed, []int{4}) {
+		t.Errorf("Expected opened [1] and closed [4], got %v and %v", opened, closed)
+	}
+	if len(changed) == 0 {
+		t.Errorf("Expected changed nodes")
+	}
+
+	expected := map[int][]int{
+		0: {1, 3},
+		1: {0, 2},
+		2: {1, 5},
+		3: {0},
+		5: {2},
+	}
+	for node, neighbors := range expected {
+		slices.Sort(g.AdjList[node])
+		if !reflect.DeepEqual(g.AdjList[node], neighbors) {
+			t.Errorf("Node %d: expected neighbors %v, got %v", node, neighbors, g.AdjList[node])
+		}
+	}
+	if _, exists := g.AdjList[4]; exists {
+		t.Errorf("Expected closed node 4 to be removed")
+	}
+}
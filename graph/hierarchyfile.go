@@ -0,0 +1,411 @@
+package graph
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"time"
+)
+
+/*
+Binary file of a built hierarchy (the Childs tree of a root graph).
+Numbers are little endian, "uvarint"/"varint" are the encodings of encoding/binary, strings are length uvarint and bytes.
+
+	magic "CVXH", version uint16
+	alpha float64, timeout per heuristic uvarint (ns)
+	metric flags byte (1 = metric, 2 = octile, 4 = costs), if costs: count uvarint and float64 per nodeid
+	root width uvarint
+	root adjacency: node count uvarint, per node ascending: nodeid delta uvarint, degree uvarint, neighbor - node varint...
+	tree in preorder, per hierarchy node:
+		height, width, offsetX, offsetY uvarint
+		grid flag byte, if 1: height*width cells uvarint (nodeid+1, 0 for obstacles)
+		nodes: count uvarint, ascending nodeids as delta uvarints
+		separator: count uvarint, ascending nodeids as delta uvarints
+		since version 2, provenance flag byte, if 1:
+			heuristic string, time uvarint (ns), balance float64,
+			attempt count uvarint, per attempt: heuristic string, outcome byte, time uvarint (ns)
+		child count uvarint
+	crc32 (IEEE) of everything before, uint32
+
+Adjacency lists of the hierarchy nodes are not stored, they are the subgraphs induced by their nodes.
+Version 1 files (without provenance) are still read.
+*/
+
+const (
+	hierarchyMagic   = "CVXH"
+	hierarchyVersion = 2
+)
+
+const (
+	metricSet = 1 << iota
+	metricOctile
+	metricCost
+)
+
+var ErrChecksum = errors.New("hierarchy file checksum mismatch")
+
+// Parameters a hierarchy was built with
+type BuildParams struct {
+	Alpha   float64
+	Timeout time.Duration // time limit per heuristic
+}
+
+// Write hierarchy of root g to a file
+func SaveHierarchy(filePath string, g *Graph, params BuildParams) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := WriteHierarchy(file, g, params); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Write hierarchy of root g in the binary hierarchy format
+func WriteHierarchy(w io.Writer, g *Graph, params BuildParams) error {
+	buf := []byte(hierarchyMagic)
+	buf = binary.LittleEndian.AppendUint16(buf, hierarchyVersion)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(params.Alpha))
+	buf = binary.AppendUvarint(buf, uint64(params.Timeout))
+
+	// metric
+	var flags byte
+	if g.Metric != nil {
+		flags |= metricSet
+		if g.Metric.Octile {
+			flags |= metricOctile
+		}
+		if g.Metric.Cost != nil {
+			flags |= metricCost
+		}
+	}
+	buf = append(buf, flags)
+	if flags&metricCost != 0 {
+		buf = binary.AppendUvarint(buf, uint64(len(g.Metric.Cost)))
+		for _, cost := range g.Metric.Cost {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(cost))
+		}
+	}
+	buf = binary.AppendUvarint(buf, uint64(g.rootWidth()))
+
+	// root adjacency
+	nodes := sortedNodes(g.AdjList)
+	buf = binary.AppendUvarint(buf, uint64(len(nodes)))
+	prev := 0
+	for _, node := range nodes {
+		buf = binary.AppendUvarint(buf, uint64(node-prev))
+		prev = node
+		buf = binary.AppendUvarint(buf, uint64(len(g.AdjList[node])))
+		for _, neighbor := range g.AdjList[node] {
+			buf = binary.AppendVarint(buf, int64(neighbor-node))
+		}
+	}
+
+	buf = appendHierarchyNode(buf, g)
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// append hierarchy node and its descendants in preorder
+func appendHierarchyNode(buf []byte, g *Graph) []byte {
+	for _, value := range []int{g.Height, g.Width, g.OffsetX, g.OffsetY} {
+		buf = binary.AppendUvarint(buf, uint64(value))
+	}
+	if g.Grid != nil {
+		buf = append(buf, 1)
+		for y := range g.Height {
+			for x := range g.Width {
+				buf = binary.AppendUvarint(buf, uint64(g.Grid[y][x]+1))
+			}
+		}
+	} else {
+		buf = append(buf, 0)
+	}
+	buf = appendIDs(buf, sortedNodes(g.AdjList))
+	buf = appendIDs(buf, g.Separator)
+	buf = appendProvenance(buf, g.Provenance())
+	buf = binary.AppendUvarint(buf, uint64(len(g.Childs)))
+	for _, child := range g.Childs {
+		buf = appendHierarchyNode(buf, child)
+	}
+	return buf
+}
+
+func appendProvenance(buf []byte, p *Provenance) []byte {
+	if p == nil {
+		return append(buf, 0)
+	}
+	buf = append(buf, 1)
+	buf = appendString(buf, p.Heuristic)
+	buf = binary.AppendUvarint(buf, uint64(p.Time))
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.Balance))
+	buf = binary.AppendUvarint(buf, uint64(len(p.Attempts)))
+	for _, attempt := range p.Attempts {
+		buf = appendString(buf, attempt.Heuristic)
+		buf = append(buf, byte(attempt.Outcome))
+		buf = binary.AppendUvarint(buf, uint64(attempt.Time))
+	}
+	return buf
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// append ascending nodeids as count and deltas
+func appendIDs(buf []byte, ids []int) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(ids)))
+	prev := 0
+	for _, id := range ids {
+		buf = binary.AppendUvarint(buf, uint64(id-prev))
+		prev = id
+	}
+	return buf
+}
+
+// Returns nodes of adjacency list in ascending order
+func sortedNodes(adjlist map[int][]int) []int {
+	nodes := make([]int, 0, len(adjlist))
+	for node := range adjlist {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// Load hierarchy file, returns root graph and the parameters it was built with
+func LoadHierarchy(filePath string) (*Graph, BuildParams, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, BuildParams{}, err
+	}
+	defer file.Close()
+
+	return ReadHierarchy(file)
+}
+
+// Read hierarchy in the binary hierarchy format, the checksum is verified before decoding
+func ReadHierarchy(r io.Reader) (*Graph, BuildParams, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, BuildParams{}, err
+	}
+	if len(data) < len(hierarchyMagic)+2+4 || string(data[:len(hierarchyMagic)]) != hierarchyMagic {
+		return nil, BuildParams{}, errors.New("not a hierarchy file")
+	}
+	body, sum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, BuildParams{}, ErrChecksum
+	}
+	version := binary.LittleEndian.Uint16(body[len(hierarchyMagic):])
+	if version < 1 || version > hierarchyVersion {
+		return nil, BuildParams{}, fmt.Errorf("unsupported hierarchy file version %d", version)
+	}
+
+	d := &decoder{buf: body[len(hierarchyMagic)+2:], version: version}
+	params := BuildParams{Alpha: d.float(), Timeout: time.Duration(d.uint64())}
+
+	// metric
+	var metric *Metric
+	flags := d.byte()
+	if flags&metricSet != 0 {
+		metric = &Metric{Octile: flags&metricOctile != 0}
+		if flags&metricCost != 0 {
+			metric.Cost = make([]float64, d.count(8))
+			for i := range metric.Cost {
+				metric.Cost[i] = d.float()
+			}
+		}
+	}
+	rootWidth := d.uvarint()
+
+	// root adjacency
+	adjlist := make(map[int][]int)
+	node := 0
+	for range d.count(2) {
+		node += d.uvarint()
+		neighbors := make([]int, d.count(1))
+		for i := range neighbors {
+			neighbors[i] = node + d.varint()
+		}
+		adjlist[node] = neighbors
+	}
+
+	g := d.hierarchyNode(adjlist, metric, rootWidth, true)
+	if d.err == nil && len(d.buf) > 0 {
+		d.fail("%d unexpected bytes after hierarchy", len(d.buf))
+	}
+	if d.err != nil {
+		return nil, BuildParams{}, d.err
+	}
+	return g, params, nil
+}
+
+// Decodes the payload of a hierarchy file, the first error is kept and stops decoding
+type decoder struct {
+	buf     []byte
+	err     error
+	version uint16
+}
+
+func (d *decoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("corrupt hierarchy file: "+format, args...)
+	}
+	d.buf = nil
+}
+
+func (d *decoder) byte() byte {
+	if len(d.buf) < 1 {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *decoder) float() float64 {
+	if len(d.buf) < 8 {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	f := math.Float64frombits(binary.LittleEndian.Uint64(d.buf))
+	d.buf = d.buf[8:]
+	return f
+}
+
+func (d *decoder) uint64() uint64 {
+	value, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail("invalid number")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return value
+}
+
+// nodeids, counts and sizes
+func (d *decoder) uvarint() int {
+	value := d.uint64()
+	if value > math.MaxInt32 {
+		d.fail("number %d out of range", value)
+		return 0
+	}
+	return int(value)
+}
+
+func (d *decoder) varint() int {
+	value, n := binary.Varint(d.buf)
+	if n <= 0 || value > math.MaxInt32 || value < math.MinInt32 {
+		d.fail("invalid number")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return int(value)
+}
+
+// reads a count of elements that need at least size bytes each
+func (d *decoder) count(size int) int {
+	count := d.uvarint()
+	if count*size > len(d.buf) {
+		d.fail("count %d exceeds data", count)
+		return 0
+	}
+	return count
+}
+
+func (d *decoder) string() string {
+	n := d.count(1)
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
+
+func (d *decoder) provenance() *Provenance {
+	if d.version < 2 || d.byte() == 0 {
+		return nil
+	}
+	p := &Provenance{Heuristic: d.string(), Time: time.Duration(d.uint64()), Balance: d.float()}
+	p.Attempts = make([]Attempt, d.count(3))
+	for i := range p.Attempts {
+		p.Attempts[i] = Attempt{Heuristic: d.string(), Outcome: Outcome(d.byte()), Time: time.Duration(d.uint64())}
+	}
+	return p
+}
+
+func (d *decoder) ids() []int {
+	ids := make([]int, d.count(1))
+	id := 0
+	for i := range ids {
+		id += d.uvarint()
+		ids[i] = id
+	}
+	return ids
+}
+
+// decode hierarchy node and its descendants, adjacency lists are induced from parent
+func (d *decoder) hierarchyNode(parent map[int][]int, metric *Metric, rootWidth int, root bool) *Graph {
+	g := &Graph{Metric: metric, RootWidth: rootWidth}
+	g.Height, g.Width, g.OffsetX, g.OffsetY = d.uvarint(), d.uvarint(), d.uvarint(), d.uvarint()
+	if d.byte() == 1 {
+		if g.Height*g.Width > len(d.buf) {
+			d.fail("grid of %dx%d exceeds data", g.Height, g.Width)
+			return nil
+		}
+		g.Grid = make([][]int, g.Height)
+		for y := range g.Grid {
+			g.Grid[y] = make([]int, g.Width)
+			for x := range g.Grid[y] {
+				g.Grid[y][x] = d.uvarint() - 1
+			}
+		}
+	}
+
+	nodes := d.ids()
+	for _, node := range nodes {
+		if _, exists := parent[node]; !exists {
+			d.fail("node %d is not part of its parent", node)
+			return nil
+		}
+	}
+	if root {
+		if len(nodes) != len(parent) {
+			d.fail("root has %d nodes, adjacency list %d", len(nodes), len(parent))
+			return nil
+		}
+		g.AdjList = parent
+	} else {
+		g.AdjList = InducedAdjlist(parent, nodes)
+	}
+
+	separator := d.ids()
+	if len(separator) > 0 {
+		g.Separator = separator
+	}
+	g.provenance = d.provenance()
+	g.Childs = make([]*Graph, d.count(1))
+	if len(g.Childs) == 0 {
+		g.Childs = nil
+	}
+	for i := range g.Childs {
+		g.Childs[i] = d.hierarchyNode(g.AdjList, metric, rootWidth, false)
+	}
+	if d.err != nil {
+		return nil
+	}
+	if g.Childs != nil && g.Separator == nil {
+		g.Separator = []int{}
+	}
+	return g
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 3x3 grid split by the middle column into two childs
+func testHierarchy() *Graph {
+	g := NewGraph(3, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, -1, 5},
+		{6, 7, 8},
+	}
+	g.Metric = &Metric{Octile: true, Cost: []float64{1, 1, 2, 1, 0, 1, 1, 1, 3}}
+	g.BuildAdjlist()
+
+	left := &Graph{AdjList: InducedAdjlist(g.AdjList, []int{0, 3, 6}), Height: 3, Width: 1, Metric: g.Metric, RootWidth: 3}
+	right := &Graph{AdjList: InducedAdjlist(g.AdjList, []int{2, 5, 8}), Height: 3, Width: 1, Metric: g.Metric, OffsetX: 2, RootWidth: 3}
+	right.Childs = []*Graph{
+		{AdjList: InducedAdjlist(g.AdjList, []int{2}), Height: 1, Width: 1, Metric: g.Metric, OffsetX: 2, RootWidth: 3},
+		{AdjList: InducedAdjlist(g.AdjList, []int{8}), Height: 1, Width: 1, Metric: g.Metric, OffsetX: 2, OffsetY: 2, RootWidth: 3},
+	}
+	right.Separator = []int{5}
+	g.Childs = []*Graph{left, right}
+	g.Separator = []int{1, 7}
+
+	g.SetProvenance(&Provenance{
+		Heuristic: "rowcol",
+		Attempts: []Attempt{
+			{Heuristic: "osp", Outcome: OutcomeTimeout, Time: time.Second},
+			{Heuristic: "rowcol", Outcome: OutcomeSuccess, Time: 3 * time.Millisecond},
+		},
+		Time:    time.Second + 3*time.Millisecond,
+		Balance: 3.0 / 8.0,
+	})
+	left.SetProvenance(&Provenance{Attempts: []Attempt{{Heuristic: "osp", Outcome: OutcomeRejected, Time: time.Microsecond}}, Time: time.Microsecond})
+	right.SetProvenance(&Provenance{
+		Heuristic: "osp",
+		Attempts:  []Attempt{{Heuristic: "osp", Outcome: OutcomeSuccess, Time: 2 * time.Microsecond}},
+		Time:      2 * time.Microsecond,
+		Balance:   1.0 / 3.0,
+	})
+	return g
+}
+
+func TestWriteReadHierarchy(t *testing.T) {
+	g := testHierarchy()
+	params := BuildParams{Alpha: 0.75, Timeout: 5 * time.Second}
+
+	var buf bytes.Buffer
+	if err := WriteHierarchy(&buf, g, params); err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+	loaded, loadedParams, err := ReadHierarchy(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected read error: %v", err)
+	}
+	if loadedParams != params {
+		t.Errorf("Expected params %v, got %v", params, loadedParams)
+	}
+	if !reflect.DeepEqual(loaded.Metric, g.Metric) {
+		t.Errorf("Expected metric %v, got %v", g.Metric, loaded.Metric)
+	}
+
+	var compare func(expected, got *Graph, path string)
+	compare = func(expected, got *Graph, path string) {
+		if !reflect.DeepEqual(expected.AdjList, got.AdjList) {
+			t.Errorf("%s: expected adjlist %v, got %v", path, expected.AdjList, got.AdjList)
+		}
+		if !reflect.DeepEqual(expected.Grid, got.Grid) {
+			t.Errorf("%s: expected grid %v, got %v", path, expected.Grid, got.Grid)
+		}
+		if !reflect.DeepEqual(expected.Separator, got.Separator) {
+			t.Errorf("%s: expected separator %v, got %v", path, expected.Separator, got.Separator)
+		}
+		if expected.Height != got.Height || expected.Width != got.Width || expected.OffsetX != got.OffsetX ||
+			expected.OffsetY != got.OffsetY || expected.RootWidth != got.RootWidth {
+			t.Errorf("%s: expected size %dx%d at (%d,%d) width %d, got %dx%d at (%d,%d) width %d", path,
+				expected.Height, expected.Width, expected.OffsetX, expected.OffsetY, expected.RootWidth,
+				got.Height, got.Width, got.OffsetX, got.OffsetY, got.RootWidth)
+		}
+		if !reflect.DeepEqual(expected.Provenance(), got.Provenance()) {
+			t.Errorf("%s: expected provenance %+v, got %+v", path, expected.Provenance(), got.Provenance())
+		}
+		if got.Metric != loaded.Metric {
+			t.Errorf("%s: expected metric shared with root", path)
+		}
+		if len(expected.Childs) != len(got.Childs) {
+			t.Errorf("%s: expected %d childs, got %d", path, len(expected.Childs), len(got.Childs))
+			return
+		}
+		for i := range expected.Childs {
+			compare(expected.Childs[i], got.Childs[i], path+"/"+string(rune('0'+i)))
+		}
+	}
+	compare(g, loaded, "root")
+}
+
+// testHierarchy without provenance written in version 1 of the format
+const hierarchyV1 = "435658480100000000000000e83f80e497d0120709000000000000f03f000000000000f03f0000000000000040000000000000f03f" +
+	"0000000000000000000000000000f03f000000000000f03f000000000000f03f0000000000000840030800020206010201020102010601020506" +
+	"0202050601020205010201020102010503030000010102030400060708090800010101020101010201060203010000000300030300000301020000" +
+	"030203030105020101020000010200000101020200010800004983a3fb"
+
+func TestReadHierarchyVersion1(t *testing.T) {
+	data, _ := hex.DecodeString(hierarchyV1)
+	loaded, params, err := ReadHierarchy(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected read error: %v", err)
+	}
+	if params != (BuildParams{Alpha: 0.75, Timeout: 5 * time.Second}) {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	var compare func(expected, got *Graph)
+	compare = func(expected, got *Graph) {
+		if !reflect.DeepEqual(expected.AdjList, got.AdjList) || !reflect.DeepEqual(expected.Separator, got.Separator) ||
+			len(expected.Childs) != len(got.Childs) {
+			t.Fatalf("Expected hierarchy node %v, got %v", expected.AdjList, got.AdjList)
+		}
+		if got.Provenance() != nil {
+			t.Errorf("Expected no provenance in version 1, got %+v", got.Provenance())
+		}
+		for i := range expected.Childs {
+			compare(expected.Childs[i], got.Childs[i])
+		}
+	}
+	compare(testHierarchy(), loaded)
+}
+
+func TestReadHierarchyErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHierarchy(&buf, testHierarchy(), BuildParams{Alpha: 0.5}); err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+	data := buf.Bytes()
+
+	// rewrites the checksum so decoding errors are reached
+	withChecksum := func(body []byte) []byte {
+		return binary.LittleEndian.AppendUint32(body, crc32.ChecksumIEEE(body))
+	}
+	flipped := bytes.Clone(data)
+	flipped[20] ^= 0xff
+	version := bytes.Clone(data[:len(data)-4])
+	version[4] = 9
+	truncated := withChecksum(bytes.Clone(data[:len(data)-10]))
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"wrong magic", append([]byte("XXXX"), data[4:]...)},
+		{"flipped byte", flipped},
+		{"unknown version", withChecksum(version)},
+		{"truncated", truncated},
+	}
+	for _, tt := range tests {
+		if _, _, err := ReadHierarchy(bytes.NewReader(tt.data)); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+	if _, _, err := ReadHierarchy(bytes.NewReader(flipped)); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Expected checksum error, got %v", err)
+	}
+}
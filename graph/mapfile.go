@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"bachelor-project/config"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// characters of the MovingAI map format, passable ones are defined in config.TerrainCosts
+const movingAIChars = ".G@OTSW"
+
+// Error in a map or scenario file, line and column start at 1 (column 0 if the whole line is affected)
+type ParseError struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Reads lines of any length, the trailing "\n" or "\r\n" is removed
+type LineReader struct {
+	reader *bufio.Reader
+	Line   int // number of the last returned line
+}
+
+func NewLineReader(r io.Reader) *LineReader {
+	return &LineReader{reader: bufio.NewReader(r)}
+}
+
+// Returns next line, io.EOF if there is none
+func (lr *LineReader) Next() (string, error) {
+	line, err := lr.reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
+		return "", err
+	}
+	lr.Line++
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return line, nil
+}
+
+// Open and parse a MovingAI map file, build grid and adjacency list
+func LoadMap(filePath string) (*Graph, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseMap(file, filePath)
+}
+
+// Parse a MovingAI map, name is used for error messages
+// the header lines "type", "height" and "width" may come in any order and are followed by "map"
+func ParseMap(r io.Reader, name string) (*Graph, error) {
+	lr := NewLineReader(r)
+	fail := func(column int, format string, args ...any) error {
+		return &ParseError{File: name, Line: lr.Line, Column: column, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	// header
+	height, width := -1, -1
+	mapType := ""
+	for {
+		line, err := lr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, &ParseError{File: name, Line: lr.Line + 1, Msg: `missing "map" line`}
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "map" && len(fields) == 1 {
+			break
+		}
+		if len(fields) != 2 {
+			return nil, fail(0, "malformed header line %q, expected <key> <value>", line)
+		}
+		// search the value only after the key, its text may also appear in the key
+		keyColumn := strings.Index(line, fields[0]) + 1
+		afterKey := keyColumn - 1 + len(fields[0])
+		valueColumn := afterKey + strings.Index(line[afterKey:], fields[1]) + 1
+		switch fields[0] {
+		case "type":
+			if mapType != "" {
+				return nil, fail(keyColumn, "duplicate type header")
+			}
+			if fields[1] != "octile" {
+				return nil, fail(valueColumn, "unsupported map type %q", fields[1])
+			}
+			mapType = fields[1]
+		case "height", "width":
+			value, err := strconv.Atoi(fields[1])
+			if err != nil || value <= 0 {
+				return nil, fail(valueColumn, "%s must be a positive integer, got %q", fields[0], fields[1])
+			}
+			target := &height
+			if fields[0] == "width" {
+				target = &width
+			}
+			if *target != -1 {
+				return nil, fail(keyColumn, "duplicate %s header", fields[0])
+			}
+			*target = value
+		default:
+			return nil, fail(keyColumn, "unknown header %q", fields[0])
+		}
+	}
+	if height == -1 {
+		return nil, fail(0, "missing height header")
+	}
+	if width == -1 {
+		return nil, fail(0, "missing width header")
+	}
+
+	// grid
+	grid := make([][]int, height)
+	costs := make([]float64, height*width)
+	weighted := false
+	for y := range height {
+		line, err := lr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, &ParseError{File: name, Line: lr.Line + 1, Msg: fmt.Sprintf("truncated grid, expected %d rows, got %d", height, y)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(line) != width {
+			return nil, fail(min(len(line), width)+1, "row has %d cells, expected %d", len(line), width)
+		}
+
+		grid[y] = make([]int, width)
+		for x := range width {
+			// passable nodes are listed in the terrain cost table
+			if cost, passable := config.TerrainCosts[line[x]]; passable {
+				grid[y][x] = NodeID(x, y, width)
+				costs[grid[y][x]] = cost
+				weighted = weighted || cost != 1
+			} else if strings.IndexByte(movingAIChars, line[x]) >= 0 {
+				grid[y][x] = -1
+			} else {
+				return nil, fail(x+1, "unknown terrain character %q", line[x])
+			}
+		}
+	}
+
+	// only empty lines may follow the grid
+	for {
+		line, err := lr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) != "" {
+			return nil, fail(1, "grid has more than %d rows", height)
+		}
+	}
+
+	//create graph object
+	graph := NewGraph(height, width)
+
+	graph.Grid = grid
+	if config.Octile || weighted {
+		graph.Metric = &Metric{Octile: config.Octile}
+		if weighted {
+			graph.Metric.Cost = costs
+		}
+	}
+
+	graph.BuildAdjlist()
+
+	return graph, nil
+}
@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMap(t *testing.T) {
+	// width before height
+	input := "type octile\r\nwidth 3\r\nheight 2\r\nmap\r\n.@.\r\n...\r\n"
+	g, err := ParseMap(strings.NewReader(input), "test.map")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedGrid := [][]int{
+		{0, -1, 2},
+		{3, 4, 5},
+	}
+	if !reflect.DeepEqual(g.Grid, expectedGrid) {
+		t.Errorf("Grid does not match expected grid.\nExpected: %v\nGot: %v", expectedGrid, g.Grid)
+	}
+	if len(g.AdjList) != 5 {
+		t.Errorf("Expected 5 nodes, got %d", len(g.AdjList))
+	}
+}
+
+func TestParseMapWideRow(t *testing.T) {
+	// rows longer than the 64KB token limit of bufio.Scanner
+	width := 70000
+	input := "type octile\nheight 1\nwidth 70000\nmap\n" + strings.Repeat(".", width) + "\n"
+	g, err := ParseMap(strings.NewReader(input), "wide.map")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(g.AdjList) != width {
+		t.Errorf("Expected %d nodes, got %d", width, len(g.AdjList))
+	}
+}
+
+func TestParseMapErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		line   int
+		column int
+	}{
+		{
+			name:   "Malformed height",
+			input:  "type octile\nheight x\nwidth 3\nmap\n...\n",
+			line:   2,
+			column: 8,
+		},
+		{
+			name:   "Value text inside key",
+			input:  "type octile\nheight h\nwidth 3\nmap\n...\n",
+			line:   2,
+			column: 8,
+		},
+		{
+			name:   "Indented header",
+			input:  "type octile\n  width  t\nheight 1\nmap\n...\n",
+			line:   2,
+			column: 10,
+		},
+		{
+			name:   "Unknown header",
+			input:  "type octile\nheight 1\ndepth 3\nmap\n...\n",
+			line:   3,
+			column: 1,
+		},
+		{
+			name:   "Unsupported type",
+			input:  "type hex\nheight 1\nwidth 3\nmap\n...\n",
+			line:   1,
+			column: 6,
+		},
+		{
+			name:   "Missing width",
+			input:  "type octile\nheight 1\nmap\n...\n",
+			line:   3,
+			column: 0,
+		},
+		{
+			name:   "Short row",
+			input:  "type octile\nheight 2\nwidth 3\nmap\n...\n..\n",
+			line:   6,
+			column: 3,
+		},
+		{
+			name:   "Long row",
+			input:  "type octile\nheight 2\nwidth 3\nmap\n....\n...\n",
+			line:   5,
+			column: 4,
+		},
+		{
+			name:   "Unknown character",
+			input:  "type octile\nheight 1\nwidth 3\nmap\n.x.\n",
+			line:   5,
+			column: 2,
+		},
+		{
+			name:   "Truncated grid",
+			input:  "type octile\nheight 3\nwidth 3\nmap\n...\n...\n",
+			line:   7,
+			column: 0,
+		},
+		{
+			name:   "Too many rows",
+			input:  "type octile\nheight 1\nwidth 3\nmap\n...\n...\n",
+			line:   6,
+			column: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := ParseMap(strings.NewReader(tc.input), "test.map")
+			if g != nil {
+				t.Errorf("Expected nil graph on error")
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected ParseError, got %v", err)
+			}
+			if parseErr.File != "test.map" || parseErr.Line != tc.line || parseErr.Column != tc.column {
+				t.Errorf("Expected position test.map:%d:%d, got %v", tc.line, tc.column, parseErr)
+			}
+		})
+	}
+}
+
+func TestLoadMap(t *testing.T) {
+	if _, err := LoadMap("does_not_exist.map"); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+	g, err := LoadMap("graph_test.txt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(g.AdjList) != 6 {
+		t.Errorf("Expected 6 nodes, got %d", len(g.AdjList))
+	}
+}
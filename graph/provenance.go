@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+)
+
+// Result of one heuristic on a hierarchy node
+type Outcome byte
+
+const (
+	OutcomeSuccess   Outcome = iota + 1 // found an alpha balanced convex decomposition
+	OutcomeRejected                     // finished without a valid decomposition
+	OutcomeTimeout                      // stopped by the time limit
+	OutcomeCancelled                    // stopped because another heuristic won (portfolio)
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeRejected:
+		return "rejected"
+	case OutcomeTimeout:
+		return "timeout"
+	case OutcomeCancelled:
+		return "cancelled"
+	}
+	return fmt.Sprintf("outcome(%d)", byte(o))
+}
+
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *Outcome) UnmarshalText(text []byte) error {
+	for _, outcome := range []Outcome{OutcomeSuccess, OutcomeRejected, OutcomeTimeout, OutcomeCancelled} {
+		if string(text) == outcome.String() {
+			*o = outcome
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown outcome %q", text)
+}
+
+// One heuristic tried on a hierarchy node
+type Attempt struct {
+	Heuristic string        `json:"heuristic"`
+	Outcome   Outcome       `json:"outcome"`
+	Time      time.Duration `json:"time"` // wall time until the result or the time limit
+}
+
+// How the split of a hierarchy node was found
+type Provenance struct {
+	Heuristic string        `json:"heuristic"` // heuristic that split the node, empty if every heuristic failed
+	Attempts  []Attempt     `json:"attempts"`  // heuristics in the order they were tried
+	Time      time.Duration `json:"time"`      // wall time of all attempts
+	Balance   float64       `json:"balance"`   // largest child / nodes, 0 if the node was not split
+}
+
+// Returns how the split of g was found, nil if no heuristic was run on g (built without provenance or less than 3 nodes)
+func (g *Graph) Provenance() *Provenance {
+	return g.provenance
+}
+
+// Store how the split of g was found
+func (g *Graph) SetProvenance(p *Provenance) {
+	g.provenance = p
+}
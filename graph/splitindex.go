@@ -0,0 +1,57 @@
+package graph
+
+import "slices"
+
+// Dense lookup tables of a split graph for queries through its separator, see Graph.SplitIndex
+type SplitIndex struct {
+	Owner     []int       // dense index -> index of the child containing the node, -1 for separator nodes
+	Separator []int       // dense indices of the separator nodes, in order of Graph.Separator
+	Distances [][]float64 // Distances[a][b] is the distance in the graph from separator node a to b, +Inf if not reachable
+
+	// state of the graph the index was built for
+	csr       *CSR
+	separator []int
+	childs    []*Graph
+}
+
+// Create index for the current CSR, separator and childs of g, the distances are filled in by the caller
+func NewSplitIndex(g *Graph) *SplitIndex {
+	c := g.Dense()
+	s := &SplitIndex{
+		Owner:     make([]int, c.Len()),
+		Separator: make([]int, 0, len(g.Separator)),
+		csr:       c,
+		separator: g.Separator,
+		childs:    slices.Clone(g.Childs),
+	}
+	for i := range s.Owner {
+		s.Owner[i] = -1
+	}
+	for k, child := range g.Childs {
+		for id := range child.AdjList {
+			if i, exists := c.Index(id); exists {
+				s.Owner[i] = k
+			}
+		}
+	}
+	for _, id := range g.Separator {
+		if i, exists := c.Index(id); exists {
+			s.Separator = append(s.Separator, i)
+		}
+	}
+	return s
+}
+
+// Returns the cached split index, nil if none was stored or the CSR, separator or childs changed since
+func (g *Graph) SplitIndex() *SplitIndex {
+	s := g.splitIndex.Load()
+	if s == nil || s.csr != g.Dense() || !slices.Equal(s.separator, g.Separator) || !slices.Equal(s.childs, g.Childs) {
+		return nil
+	}
+	return s
+}
+
+// Store split index of g
+func (g *Graph) SetSplitIndex(s *SplitIndex) {
+	g.splitIndex.Store(s)
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitIndex(t *testing.T) {
+	// 0 1 2
+	// 3 4 5
+	// split into left and right column, separator 1 4
+	newSplit := func() *Graph {
+		g := NewGraph(2, 3)
+		g.Grid = [][]int{{0, 1, 2}, {3, 4, 5}}
+		g.BuildAdjlist()
+		g.Childs = []*Graph{
+			{AdjList: InducedAdjlist(g.AdjList, []int{0, 3})},
+			{AdjList: InducedAdjlist(g.AdjList, []int{2, 5})},
+		}
+		g.Separator = []int{1, 4}
+		return g
+	}
+	g := newSplit()
+	s := NewSplitIndex(g)
+	if !reflect.DeepEqual(s.Owner, []int{0, -1, 1, 0, -1, 1}) || !reflect.DeepEqual(s.Separator, []int{1, 4}) {
+		t.Errorf("Expected owners [0 -1 1 0 -1 1] and separator [1 4], got %v, %v", s.Owner, s.Separator)
+	}
+
+	if g.SplitIndex() != nil {
+		t.Errorf("Expected no index before it is stored")
+	}
+	g.SetSplitIndex(s)
+	if g.SplitIndex() != s {
+		t.Errorf("Expected stored index")
+	}
+
+	testCases := []struct {
+		name   string
+		change func(g *Graph)
+	}{
+		{"Separator", func(g *Graph) { g.Separator = []int{1} }},
+		{"Childs", func(g *Graph) { g.Childs = g.Childs[:1] }},
+		{"Adjacency list", func(g *Graph) { g.AddEdge(0, 4) }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changed := newSplit()
+			changed.SetSplitIndex(NewSplitIndex(changed))
+			tc.change(changed)
+			if changed.SplitIndex() != nil {
+				t.Errorf("Expected index to be dropped after the change")
+			}
+		})
+	}
+}
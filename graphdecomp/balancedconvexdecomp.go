@@ -118,7 +118,7 @@ func OneShortestPathBalancedConvexDecomposition(g *graph.Graph, separator []int,
 			}
 		}
 		if checkBalanced(parent, len(g.AdjList)) {
-			if degreeFour(g, separator) {
+			if !g.Weighted() && degreeFour(g, separator) {
 				return decomposeGraph(g, parent), true
 			}
 			if checkObservationAndConvexity(g, copyAdjlist, parent, separator, ctx) {
@@ -144,7 +144,7 @@ func CheckOneShortestPathBalancedConvex(g *graph.Graph, separator []int, ctx con
 		parent := unionFind(copyAdjlist)
 
 		if checkBalanced(parent, len(g.AdjList)) {
-			if degreeFour(g, separator) {
+			if !g.Weighted() && degreeFour(g, separator) {
 				return true
 			}
 			if checkObservationAndConvexity(g, copyAdjlist, parent, separator, ctx) {
@@ -189,6 +189,7 @@ func decomposeGraph(g *graph.Graph, parent map[int]int) []*graph.Graph {
 	xLeft, xRight := g.Width, -1
 
 	sizeNodes := make(map[int][]int) // key = connected components, values = coordinates for size
+	roots := []int{}                 // roots in order of their first node in the grid, gives childs a stable order
 
 	// Initialize values for comparison
 	for key, val := range parent {
@@ -205,6 +206,9 @@ func decomposeGraph(g *graph.Graph, parent map[int]int) []*graph.Graph {
 			// if node is -1 non passable node it shouldnt exist in parent (-1 can't be a nodeid)
 			root, exists := parent[node]
 			if exists {
+				if sizeNodes[root][1] == -1 { // first node of the component
+					roots = append(roots, root)
+				}
 				// Sequential if statements cause a subgraph can be for example one node, a row of nodes, a column...
 				// access coordinates of connected component via sizeNodes[root]
 				if y < sizeNodes[root][0] {
@@ -228,7 +232,7 @@ func decomposeGraph(g *graph.Graph, parent map[int]int) []*graph.Graph {
 	// create children array for original graph
 	subgraphes := make([]*graph.Graph, 0, len(sizeNodes))
 	// key is root of component
-	for key := range sizeNodes {
+	for _, key := range roots {
 		yTop, yLow = sizeNodes[key][0], sizeNodes[key][1]
 		xLeft, xRight = sizeNodes[key][2], sizeNodes[key][3]
 		height := yLow - yTop + 1
@@ -267,8 +271,109 @@ func decomposeGraph(g *graph.Graph, parent map[int]int) []*graph.Graph {
 			ySub++
 		}
 		subgraph.Grid = subgrid
-		subgraph.BuildAdjlist()
+		subgraph.Metric = g.Metric
+		// keep position in the root grid, nodeids stay those of the root
+		subgraph.OffsetX = g.OffsetX + xLeft
+		subgraph.OffsetY = g.OffsetY + yTop
+		subgraph.RootWidth = g.RootWidth
+		if subgraph.RootWidth == 0 { // g was not created by graph.NewGraph
+			subgraph.RootWidth = g.Width
+		}
+		// induced subgraph of g, rebuilding from the grid would drop diagonals next to separator nodes
+		for y := range height {
+			for x := range width {
+				node := subgrid[y][x]
+				if node == -1 {
+					continue
+				}
+				subgraph.AdjList[node] = make([]int, 0, len(g.AdjList[node]))
+				for _, neighbor := range g.AdjList[node] {
+					if root, exists := parent[neighbor]; exists && root == key {
+						subgraph.AdjList[node] = append(subgraph.AdjList[node], neighbor)
+					}
+				}
+			}
+		}
 		subgraphes = append(subgraphes, subgraph)
 	}
 	return subgraphes
 }
+
+// Checks if childs are a valid decomposition of g: every child is exactly one connected component of g
+// without the separator (nodes of g in no child), the components are alpha balanced and convex.
+// Childs that are the connected components of g itself need no balance, like in DecomposeInputComponents
+func CheckDecomposition(g *graph.Graph, childs []*graph.Graph, ctx context.Context) bool {
+	if len(childs) < 2 {
+		return false
+	}
+	owner := make(map[int]int, len(g.AdjList)) // node -> index of child
+	for i, child := range childs {
+		for node := range child.AdjList {
+			if _, exists := g.AdjList[node]; !exists {
+				return false
+			}
+			if _, exists := owner[node]; exists {
+				return false
+			}
+			owner[node] = i
+		}
+	}
+
+	// Create adjacency list of all subgraphes combined into list
+	copyAdjlist := g.CopyAdjlist()
+	separator := []int{}
+	for node := range g.AdjList {
+		if _, exists := owner[node]; !exists {
+			separator = append(separator, node)
+		}
+	}
+	for _, node := range separator {
+		graph.RemoveNode(copyAdjlist, node)
+	}
+	parent := unionFind(copyAdjlist)
+
+	// every child has to be exactly one connected component
+	rootOfChild := make(map[int]int, len(childs))
+	childOfRoot := make(map[int]int, len(childs))
+	for node, i := range owner {
+		root := parent[node]
+		if r, exists := rootOfChild[i]; exists && r != root {
+			return false // child is split into several components
+		}
+		if c, exists := childOfRoot[root]; exists && c != i {
+			return false // two childs are connected
+		}
+		rootOfChild[i] = root
+		childOfRoot[root] = i
+	}
+	if len(rootOfChild) != len(childs) {
+		return false // empty child
+	}
+
+	if len(separator) == 0 {
+		return true
+	}
+	return checkBalanced(parent, len(g.AdjList)) && checkConvexity(g, copyAdjlist, parent, ctx)
+}
+
+// Restores grid of a subgraph whose Grid was dropped, from the grid of its root graph.
+// The grid is cropped to the bounding box of the subgraph nodes
+func RestoreGrid(root *graph.Graph, g *graph.Graph) {
+	parent := make(map[int]int, len(g.AdjList))
+	key := -1
+	for node := range g.AdjList {
+		if key == -1 {
+			key = node
+		}
+		parent[node] = key // every node in one component
+	}
+	if key == -1 {
+		g.Grid = [][]int{}
+		g.Height, g.Width = 0, 0
+		return
+	}
+	sub := decomposeGraph(root, parent)[0]
+	g.Grid = sub.Grid
+	g.Height, g.Width = sub.Height, sub.Width
+	g.OffsetX, g.OffsetY, g.RootWidth = sub.OffsetX, sub.OffsetY, sub.RootWidth
+}
@@ -4,6 +4,7 @@ import (
 	"bachelor-project/config"
 	"bachelor-project/graph"
 	"context"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -168,3 +169,113 @@ func TestDecomposeInputComponents(t *testing.T) {
 	}
 
 }
+
+func TestDecomposeGraphOffsets(t *testing.T) {
+	/*
+		0  1  2  3
+		4  5  6  7
+		8  9  10 11
+		12 13 14 15
+	*/
+	g := graph.NewGraph(4, 4)
+	g.Grid = [][]int{
+		{0, 1, 2, 3},
+		{4, 5, 6, 7},
+		{8, 9, 10, 11},
+		{12, 13, 14, 15},
+	}
+	g.BuildAdjlist()
+
+	// remove column 1, then row 1 of the right component
+	copy := g.CopyAdjlist()
+	for _, node := range []int{1, 5, 9, 13} {
+		graph.RemoveNode(copy, node)
+	}
+	childs := decomposeGraph(g, unionFind(copy))
+
+	grandchilds := []*graph.Graph{}
+	for _, child := range childs {
+		copy := child.CopyAdjlist()
+		if _, exists := copy[6]; exists {
+			for _, node := range []int{6, 7} {
+				graph.RemoveNode(copy, node)
+			}
+			grandchilds = append(grandchilds, decomposeGraph(child, unionFind(copy))...)
+		}
+	}
+	if len(childs) != 2 || len(grandchilds) != 2 {
+		t.Fatalf("Expected 2 childs and 2 grandchilds, got %d and %d", len(childs), len(grandchilds))
+	}
+
+	// every node must be found at its local cell
+	for _, sg := range append(childs, grandchilds...) {
+		if sg.RootWidth != 4 {
+			t.Errorf("Expected root width 4, got %d", sg.RootWidth)
+		}
+		for id := range sg.AdjList {
+			x, y, ok := sg.LocalFromNodeID(id)
+			if !ok || sg.Grid[y][x] != id || sg.NodeIDAt(x, y) != id {
+				t.Errorf("Node %d not found at local cell (%d,%d) of subgraph with offset (%d,%d)", id, x, y, sg.OffsetX, sg.OffsetY)
+			}
+		}
+	}
+}
+
+func TestCheckDecomposition(t *testing.T) {
+	g := graph.NewGraph(3, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, 4, 5},
+		{6, 7, 8},
+	}
+	g.BuildAdjlist()
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// middle column as separator
+	copy := g.CopyAdjlist()
+	for _, node := range []int{1, 4, 7} {
+		graph.RemoveNode(copy, node)
+	}
+	childs := decomposeGraph(g, unionFind(copy))
+	if !CheckDecomposition(g, childs, ctx) {
+		t.Errorf("Expected valid decomposition")
+	}
+
+	// node in two childs
+	childs[0].AdjList[4], childs[1].AdjList[4] = []int{}, []int{}
+	if CheckDecomposition(g, childs, ctx) {
+		t.Errorf("Expected invalid decomposition for node in two childs")
+	}
+
+	// child not connected on its own
+	split := &graph.Graph{AdjList: map[int][]int{0: {3}, 3: {0}, 2: {5}, 5: {2}}}
+	rest := &graph.Graph{AdjList: map[int][]int{6: {7}, 7: {6, 8}, 8: {7}}}
+	if CheckDecomposition(g, []*graph.Graph{split, rest}, ctx) {
+		t.Errorf("Expected invalid decomposition for disconnected child")
+	}
+}
+
+func TestRestoreGrid(t *testing.T) {
+	root := graph.NewGraph(3, 4)
+	root.Grid = [][]int{
+		{0, 1, 2, 3},
+		{4, -1, 6, 7},
+		{8, 9, 10, 11},
+	}
+	root.BuildAdjlist()
+
+	g := &graph.Graph{AdjList: map[int][]int{6: {7}, 7: {6, 11}, 11: {7}}}
+	RestoreGrid(root, g)
+
+	expected := [][]int{
+		{6, 7},
+		{-1, 11},
+	}
+	if !reflect.DeepEqual(g.Grid, expected) {
+		t.Errorf("Expected grid %v, got %v", expected, g.Grid)
+	}
+	if g.OffsetX != 2 || g.OffsetY != 1 || g.RootWidth != 4 {
+		t.Errorf("Expected offset (2,1) and root width 4, got (%d,%d) and %d", g.OffsetX, g.OffsetY, g.RootWidth)
+	}
+}
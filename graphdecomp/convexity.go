@@ -2,13 +2,16 @@ package graphdecomp
 
 import (
 	"bachelor-project/graph"
+	"container/heap"
 	"context"
+	"math"
 )
 
 // check original graph and subgraph for convexity with help of their adjancy lists
 // expects already adjlist of subgraph and parent map (union find)
 func checkConvexity(g *graph.Graph, adjlist map[int][]int, parent map[int]int, ctx context.Context) bool {
-	boundaryNodes := extractBorderNodesOfComponents(adjlist, parent)
+	boundaryNodes := extractBorderNodesOfComponents(adjlist, parent, g.MaxDegree())
+	check := newConvexityCheck(g, adjlist)
 	// check every connected component
 	for _, boundaryList := range boundaryNodes {
 		for _, node := range boundaryList {
@@ -20,9 +23,7 @@ func checkConvexity(g *graph.Graph, adjlist map[int][]int, parent map[int]int, c
 				// proceed
 			}
 			// start bfs for every boundary node per component
-			distSub, maxDepth := bfs(adjlist, node)                //compute distance to each other node and max depth of bfs
-			filterDistancesForBoundary(adjlist, distSub)           //filter dist map only for boundary nodes
-			if !isNodeConvex(g.AdjList, distSub, node, maxDepth) { // check for same distance between nodes in original graph
+			if !check.isConvex(node) {
 				return false
 			}
 		}
@@ -32,8 +33,9 @@ func checkConvexity(g *graph.Graph, adjlist map[int][]int, parent map[int]int, c
 }
 
 func checkObservationAndConvexity(g *graph.Graph, adjlist map[int][]int, parent map[int]int, separator []int, ctx context.Context) bool {
-	boundaryNodes := extractBorderNodesOfComponents(adjlist, parent)
+	boundaryNodes := extractBorderNodesOfComponents(adjlist, parent, g.MaxDegree())
 	adjacentNodes := getAdjacentNodesOfSeparator(g, separator, parent)
+	var check *convexityCheck // built lazily, observation 7 often skips every bfs
 
 	// Adjlist for coordinates
 	coordAdjlist := make(map[int]int, len(g.AdjList))
@@ -49,8 +51,11 @@ func checkObservationAndConvexity(g *graph.Graph, adjlist map[int][]int, parent
 	}
 
 	for root, boundaryList := range boundaryNodes {
-		// check if observation 7 applies to skip convexity check with bfs
-		if !checkObservation(g, coordAdjlist, adjacentNodes[root]) {
+		// check if observation 7 applies to skip convexity check with bfs, it only holds for 4-connected unit cost grids
+		if g.Weighted() || !checkObservation(g, coordAdjlist, adjacentNodes[root]) {
+			if check == nil {
+				check = newConvexityCheck(g, adjlist)
+			}
 			for _, node := range boundaryList {
 				select {
 				case <-ctx.Done():
@@ -59,9 +64,7 @@ func checkObservationAndConvexity(g *graph.Graph, adjlist map[int][]int, parent
 					// proceed
 				}
 				// start bfs for every boundary node per component
-				distSub, maxDepth := bfs(adjlist, node)                // compute distance to each other node and max depth of bfs
-				filterDistancesForBoundary(adjlist, distSub)           // filter dist map only for boundary nodes
-				if !isNodeConvex(g.AdjList, distSub, node, maxDepth) { // check for same distance between nodes in original graph
+				if !check.isConvex(node) {
 					return false
 				}
 			}
@@ -141,85 +144,215 @@ func getAdjacentNodesOfSeparator(g *graph.Graph, separators []int, parent map[in
 	return adjacentNodes
 }
 
-// filter dist map for boundary nodes
-func filterDistancesForBoundary(adjlist map[int][]int, dist map[int]int) {
-	for key := range dist {
-		if len(adjlist[key]) == 4 {
-			delete(dist, key)
+// filter dist slice for boundary nodes, inner nodes (deg(v)=maxDegree) are set to -1
+func filterDistancesForBoundary(c *graph.CSR, dist []int, maxDegree int) {
+	for i := range dist {
+		if dist[i] >= 0 && c.Degree(i) == maxDegree {
+			dist[i] = -1
 		}
 	}
 }
 
-// Returns boundary nodes (deg(v)<4) of a given Graph for each connected component
-func extractBorderNodesOfComponents(adjlist map[int][]int, parent map[int]int) map[int][]int {
+// Returns boundary nodes (deg(v)<maxDegree) of a given Graph for each connected component
+func extractBorderNodesOfComponents(adjlist map[int][]int, parent map[int]int, maxDegree int) map[int][]int {
 	boundaryNodes := make(map[int][]int) // key = root of connected component, values = all nodes in same connected component
 
 	for node := range adjlist {
-		if len(adjlist[node]) < 4 {
+		if len(adjlist[node]) < maxDegree {
 			boundaryNodes[parent[node]] = append(boundaryNodes[parent[node]], node)
 		}
 	}
 	return boundaryNodes
 }
 
-// Returns one-to-many distance relationship via bfs
-func bfs(adjlist map[int][]int, start int) (map[int]int, int) {
-	dist := make(map[int]int) // store distances to each node
-	visited := make(map[int]struct{})
+// reusable buffers for repeated searches on the same graph
+// dist[i] == -1 marks dense index i as not visited
+type scratch struct {
+	dist  []int
+	queue []int          // every visited node, used for resetting
+	cost  []float64      // dijkstra only, allocated on first use
+	heap  graph.CostHeap // dijkstra only
+}
+
+func newScratch(n int) *scratch {
+	s := &scratch{
+		dist:  make([]int, n),
+		queue: make([]int, 0, n),
+	}
+	for i := range s.dist {
+		s.dist[i] = -1
+	}
+	return s
+}
+
+// mark every node visited by the previous search as not visited
+func (s *scratch) reset() {
+	for _, i := range s.queue {
+		s.dist[i] = -1
+		if s.cost != nil {
+			s.cost[i] = math.Inf(1)
+		}
+	}
+	s.queue = s.queue[:0]
+	s.heap = s.heap[:0]
+}
+
+// dense state shared by every bfs of one convexity check
+type convexityCheck struct {
+	orig      *graph.CSR
+	sub       *graph.CSR
+	subToOrig []int     // dense index in subgraph -> dense index in original graph
+	distOrig  []int     // boundary distances of subgraph on original indices, -1 if none
+	costOrig  []float64 // weighted graphs only, boundary costs on original indices, -1 if none
+	maxDegree int
+	subBuf    *scratch
+	origBuf   *scratch
+}
+
+func newConvexityCheck(g *graph.Graph, adjlist map[int][]int) *convexityCheck {
+	orig := g.Dense()
+	var sub *graph.CSR
+	var costOrig []float64
+	if g.Weighted() {
+		sub = graph.NewWeightedCSR(adjlist, g.EdgeCost)
+		costOrig = make([]float64, orig.Len())
+		for i := range costOrig {
+			costOrig[i] = -1
+		}
+	} else {
+		sub = graph.NewCSR(adjlist)
+	}
+
+	subToOrig := make([]int, sub.Len())
+	for i, id := range sub.NodeIDs {
+		subToOrig[i], _ = orig.Index(id)
+	}
+	distOrig := make([]int, orig.Len())
+	for i := range distOrig {
+		distOrig[i] = -1
+	}
+
+	return &convexityCheck{
+		orig:      orig,
+		sub:       sub,
+		subToOrig: subToOrig,
+		distOrig:  distOrig,
+		costOrig:  costOrig,
+		maxDegree: g.MaxDegree(),
+		subBuf:    newScratch(sub.Len()),
+		origBuf:   newScratch(orig.Len()),
+	}
+}
+
+// Checks if distances of a boundary node to all other boundary nodes are equal in subgraph and original graph
+func (cc *convexityCheck) isConvex(node int) bool {
+	start, _ := cc.sub.Index(node)
+	if cc.costOrig != nil {
+		return cc.isConvexWeighted(start)
+	}
+	distSub, maxDepth := bfs(cc.sub, start, cc.subBuf)        // compute distance to each other node and max depth of bfs
+	filterDistancesForBoundary(cc.sub, distSub, cc.maxDegree) // filter distances only for boundary nodes
+
+	// translate distances onto indices of the original graph
+	count := 0
+	for _, i := range cc.subBuf.queue {
+		if distSub[i] >= 0 {
+			cc.distOrig[cc.subToOrig[i]] = distSub[i]
+			count++
+		}
+	}
+
+	convex := isNodeConvex(cc.orig, cc.distOrig, count, cc.subToOrig[start], maxDepth, cc.origBuf) // check for same distance between nodes in original graph
+
+	for _, i := range cc.subBuf.queue {
+		cc.distOrig[cc.subToOrig[i]] = -1
+	}
+	return convex
+}
+
+// Same as isConvex for graphs with edge costs, distances are computed with dijkstra
+func (cc *convexityCheck) isConvexWeighted(start int) bool {
+	costSub, maxCost := dijkstra(cc.sub, start, cc.subBuf)
 
-	queue := []int{start}
-	dist[start] = 0             // distance from start to start is 0
-	visited[start] = struct{}{} // set true
-	head := 0                   // pointer for avoiding sclice copys
+	// translate costs of boundary nodes onto indices of the original graph
+	count := 0
+	for _, i := range cc.subBuf.queue {
+		if cc.sub.Degree(i) < cc.maxDegree {
+			cc.costOrig[cc.subToOrig[i]] = costSub[i]
+			count++
+		}
+	}
+
+	convex := isNodeConvexWeighted(cc.orig, cc.costOrig, count, cc.subToOrig[start], maxCost, cc.origBuf)
+
+	for _, i := range cc.subBuf.queue {
+		cc.costOrig[cc.subToOrig[i]] = -1
+	}
+	return convex
+}
 
-	for head < len(queue) {
-		current := queue[head]
+// Returns one-to-many distance relationship via bfs on dense indices (-1 for unreachable nodes)
+// the returned slice belongs to buf and is valid till the next search with buf
+func bfs(c *graph.CSR, start int, buf *scratch) ([]int, int) {
+	if buf == nil {
+		buf = newScratch(c.Len())
+	}
+	buf.reset()
+	dist := buf.dist // store distances to each node
+
+	buf.queue = append(buf.queue, start)
+	dist[start] = 0 // distance from start to start is 0
+	head := 0       // pointer for avoiding sclice copys
+
+	for head < len(buf.queue) {
+		current := buf.queue[head]
 		head++
 		// visit all neighbors
-		for _, neighbor := range adjlist[current] {
+		for _, neighbor := range c.Adjacent(current) {
 			// check if neighbor was already visited
-			if _, exists := visited[neighbor]; !exists {
-				visited[neighbor] = struct{}{}     // set true
-				dist[neighbor] = dist[current] + 1 // store distance
-				queue = append(queue, neighbor)    // push to queue to visit its neighbors later
+			if dist[neighbor] == -1 {
+				dist[neighbor] = dist[current] + 1      // store distance
+				buf.queue = append(buf.queue, neighbor) // push to queue to visit its neighbors later
 			}
 		}
 	}
-	maxDepth := 0
-	for _, distance := range dist {
-		if maxDepth < distance {
-			maxDepth = distance
-		}
-	}
+	// bfs visits nodes in ascending distance, last node has max depth
+	maxDepth := dist[buf.queue[len(buf.queue)-1]]
 
 	return dist, maxDepth
 }
 
 // Checks if distances of a node to all other border nodes in subgraph are similar in original graph
-func isNodeConvex(originalAdj map[int][]int, distSub map[int]int, start int, maxDepth int) bool {
-	visited := make(map[int]struct{})
-	queue := []int{start}
-	visited[start] = struct{}{} // set true
+// distSub holds subgraph distances on dense indices of the original graph (-1 for non border nodes),
+// subNodeCount is the number of border nodes in distSub
+func isNodeConvex(orig *graph.CSR, distSub []int, subNodeCount int, start int, maxDepth int, buf *scratch) bool {
+	if buf == nil {
+		buf = newScratch(orig.Len())
+	}
+	buf.reset()
+	visited := buf.dist // -1 for not visited nodes
+	buf.queue = append(buf.queue, start)
+	visited[start] = 0
 
-	depth := 0                   // store current depth for early abortion
-	subVisitedCount := 1         // trace how many bordernodes of subgraph were visited
-	subNodeCount := len(distSub) // number of bordernodes that needs to be visited
+	depth := 0           // store current depth for early abortion
+	subVisitedCount := 1 // trace how many bordernodes of subgraph were visited
 
 	head := 0 // pointer
 
-	for head < len(queue) && depth <= maxDepth {
-		levelSize := len(queue) - head
+	for head < len(buf.queue) && depth <= maxDepth {
+		levelSize := len(buf.queue) - head
 		for range levelSize {
-			current := queue[head]
+			current := buf.queue[head]
 			head++
 
 			// visit all neighbors
-			for _, neighbor := range originalAdj[current] {
+			for _, neighbor := range orig.Adjacent(current) {
 				// visit non-visited neighbors
-				if _, exists := visited[neighbor]; !exists {
-					visited[neighbor] = struct{}{} // set true
+				if visited[neighbor] == -1 {
+					visited[neighbor] = depth + 1
+					buf.queue = append(buf.queue, neighbor) // queue before possible return, reset relies on it
 					// check if visited node is a border node in subgraph
-					if subDist, inSub := distSub[neighbor]; inSub {
+					if subDist := distSub[neighbor]; subDist >= 0 {
 						subVisitedCount++
 						if depth+1 < subDist {
 							return false
@@ -229,7 +362,6 @@ func isNodeConvex(originalAdj map[int][]int, distSub map[int]int, start int, max
 							return true
 						}
 					}
-					queue = append(queue, neighbor)
 				}
 			}
 		}
@@ -238,3 +370,116 @@ func isNodeConvex(originalAdj map[int][]int, distSub map[int]int, start int, max
 
 	return true
 }
+
+// tolerance for comparing sums of edge costs
+const eps = 1e-9
+
+// Returns one-to-many costs via dijkstra on dense indices (+Inf for unreachable nodes) and the max cost
+// buf.dist holds the state of each node: -1 not visited, 0 queued, 1 settled
+// the returned slice belongs to buf and is valid till the next search with buf
+func dijkstra(c *graph.CSR, start int, buf *scratch) ([]float64, float64) {
+	if buf == nil {
+		buf = newScratch(c.Len())
+	}
+	if buf.cost == nil {
+		buf.cost = make([]float64, c.Len())
+		for i := range buf.cost {
+			buf.cost[i] = math.Inf(1)
+		}
+	}
+	buf.reset()
+	state, cost := buf.dist, buf.cost
+
+	state[start] = 0
+	cost[start] = 0
+	buf.queue = append(buf.queue, start)
+	heap.Push(&buf.heap, graph.CostItem{Node: start, Cost: 0})
+	maxCost := 0.0
+
+	for buf.heap.Len() > 0 {
+		item := heap.Pop(&buf.heap).(graph.CostItem)
+		// skip outdated entries
+		if state[item.Node] == 1 || item.Cost > cost[item.Node] {
+			continue
+		}
+		state[item.Node] = 1 // settled
+		maxCost = item.Cost
+
+		weights := c.AdjacentWeights(item.Node)
+		for k, neighbor := range c.Adjacent(item.Node) {
+			newCost := item.Cost + weights[k]
+			if state[neighbor] == -1 {
+				state[neighbor] = 0
+				buf.queue = append(buf.queue, neighbor)
+			} else if state[neighbor] == 1 || newCost >= cost[neighbor] {
+				continue
+			}
+			cost[neighbor] = newCost
+			heap.Push(&buf.heap, graph.CostItem{Node: neighbor, Cost: newCost})
+		}
+	}
+
+	return cost, maxCost
+}
+
+// Checks if costs of a node to all other border nodes in subgraph are similar in original graph
+// costSub holds subgraph costs on dense indices of the original graph (-1 for non border nodes),
+// subNodeCount is the number of border nodes in costSub
+func isNodeConvexWeighted(orig *graph.CSR, costSub []float64, subNodeCount int, start int, maxCost float64, buf *scratch) bool {
+	if buf == nil {
+		buf = newScratch(orig.Len())
+	}
+	if buf.cost == nil {
+		buf.cost = make([]float64, orig.Len())
+		for i := range buf.cost {
+			buf.cost[i] = math.Inf(1)
+		}
+	}
+	buf.reset()
+	state, cost := buf.dist, buf.cost
+
+	state[start] = 0
+	cost[start] = 0
+	buf.queue = append(buf.queue, start)
+	heap.Push(&buf.heap, graph.CostItem{Node: start, Cost: 0})
+	subVisitedCount := 0 // trace how many bordernodes of subgraph were settled
+
+	for buf.heap.Len() > 0 {
+		item := heap.Pop(&buf.heap).(graph.CostItem)
+		if state[item.Node] == 1 || item.Cost > cost[item.Node] {
+			continue
+		}
+		// early abortion, every node of subgraph is closer than current node
+		if item.Cost > maxCost+eps {
+			return true
+		}
+		state[item.Node] = 1
+
+		// check if settled node is a border node in subgraph
+		if subCost := costSub[item.Node]; subCost >= 0 {
+			subVisitedCount++
+			if item.Cost < subCost-eps {
+				return false
+			}
+			// early abort condition, if all border nodes of subgraph were settled
+			if subVisitedCount == subNodeCount {
+				return true
+			}
+		}
+
+		weights := orig.AdjacentWeights(item.Node)
+		for k, neighbor := range orig.Adjacent(item.Node) {
+			newCost := item.Cost + weights[k]
+			if state[neighbor] == -1 {
+				state[neighbor] = 0
+				buf.queue = append(buf.queue, neighbor)
+			} else if state[neighbor] == 1 || newCost >= cost[neighbor] {
+				continue
+			}
+			cost[neighbor] = newCost
+			heap.Push(&buf.heap, graph.CostItem{Node: neighbor, Cost: newCost})
+		}
+	}
+
+	return true
+}
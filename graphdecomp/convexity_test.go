@@ -28,6 +28,19 @@ func makeTestGraph1() *graph.Graph {
 	}
 }
 
+// translate distances of nodeids into a dense slice of c (-1 for missing nodes)
+func denseDistances(c *graph.CSR, dist map[int]int) []int {
+	dense := make([]int, c.Len())
+	for i, id := range c.NodeIDs {
+		if d, exists := dist[id]; exists {
+			dense[i] = d
+		} else {
+			dense[i] = -1
+		}
+	}
+	return dense
+}
+
 func TestExtractBorderNodesOfComponents(t *testing.T) {
 	adjList := map[int][]int{
 		1:  {5},
@@ -53,7 +66,7 @@ func TestExtractBorderNodesOfComponents(t *testing.T) {
 		15: 11,
 	}
 
-	boundaries := extractBorderNodesOfComponents(adjList, parent)
+	boundaries := extractBorderNodesOfComponents(adjList, parent, 4)
 
 	if len(boundaries) != 3 {
 		t.Errorf("Expected 3 components, got %d", len(boundaries))
@@ -112,14 +125,17 @@ func TestBFS(t *testing.T) {
 		24: {19, 23},
 	}
 
-	dist, maxDepth := bfs(adjList, 1)
+	c := graph.NewCSR(adjList)
+	start, _ := c.Index(1)
+	dist, maxDepth := bfs(c, start, nil)
 
-	if dist[1] != 0 {
-		t.Errorf("Distance to start node 0 should be 0, got %d", dist[0])
+	if dist[start] != 0 {
+		t.Errorf("Distance to start node 0 should be 0, got %d", dist[start])
 	}
 
-	if dist[21] != 6 {
-		t.Errorf("Distance to node 2 should be 2, got %d", dist[2])
+	i, _ := c.Index(21)
+	if dist[i] != 6 {
+		t.Errorf("Distance to node 2 should be 2, got %d", dist[i])
 	}
 
 	if maxDepth != 8 {
@@ -173,15 +189,17 @@ func TestFilterDistancesForBoundary(t *testing.T) {
 	removed := []int{5, 9, 10}
 	shouldRemain := []int{0, 1, 3, 4, 6, 7, 8, 11, 12, 13, 14, 15}
 
-	filterDistancesForBoundary(originalAdj, distSub)
+	c := graph.NewCSR(originalAdj)
+	dist := denseDistances(c, distSub)
+	filterDistancesForBoundary(c, dist, 4)
 
 	for _, node := range removed {
-		if _, ok := distSub[node]; ok {
+		if i, _ := c.Index(node); dist[i] != -1 {
 			t.Errorf("Node %d should have been removed from distSub but is still present", node)
 		}
 	}
 	for _, node := range shouldRemain {
-		if _, ok := distSub[node]; !ok {
+		if i, _ := c.Index(node); dist[i] == -1 {
 			t.Errorf("Node %d should still be present but was removed", node)
 		}
 	}
@@ -218,10 +236,11 @@ func TestIsNodeConvex(t *testing.T) {
 		4: 1,
 		5: 0,
 	}
-	start := 5
+	c := graph.NewCSR(originalAdj)
+	start, _ := c.Index(5)
 	maxDepth := 2
 
-	if !isNodeConvex(originalAdj, distSub, start, maxDepth) {
+	if !isNodeConvex(c, denseDistances(c, distSub), len(distSub), start, maxDepth, nil) {
 		t.Errorf("Expected node to be convex")
 	}
 
@@ -233,10 +252,10 @@ func TestIsNodeConvex(t *testing.T) {
 		12: 1,
 		13: 0,
 	}
-	start = 13
+	start, _ = c.Index(13)
 	maxDepth = 4
 
-	if isNodeConvex(originalAdj, distSubFalse, start, maxDepth) {
+	if isNodeConvex(c, denseDistances(c, distSubFalse), len(distSubFalse), start, maxDepth, nil) {
 		t.Errorf("Expected node to be non-convex")
 	}
 }
@@ -421,3 +440,105 @@ func TestCheckObservationAndConvexity(t *testing.T) {
 	}
 
 }
+
+func TestCheckConvexityOctile(t *testing.T) {
+	/*
+		0 1 2
+		3 4 5
+		6 7 8
+	*/
+	testcases := []struct {
+		name      string
+		separator []int
+		expected  bool
+	}{
+		{
+			// ring around 4, diagonal 0-4-8 is shorter than any path along the ring
+			name:      "Non-convex",
+			separator: []int{4},
+			expected:  false,
+		},
+		{
+			name:      "Convex",
+			separator: []int{3, 4, 5},
+			expected:  true,
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := graph.NewGraph(3, 3)
+			g.Grid = [][]int{
+				{0, 1, 2},
+				{3, 4, 5},
+				{6, 7, 8},
+			}
+			g.Metric = &graph.Metric{Octile: true}
+			g.BuildAdjlist()
+
+			adjlist := g.CopyAdjlist()
+			for _, node := range tc.separator {
+				graph.RemoveNode(adjlist, node)
+			}
+			parent := unionFind(adjlist)
+
+			result := checkConvexity(g, adjlist, parent, ctx)
+			if result != tc.expected {
+				t.Errorf("Test case '%s' failed: expected %v, got %v", tc.name, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	/*
+		0 1 2
+		3 4 5
+		cost of node 1 is 5
+	*/
+	g := graph.NewGraph(2, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, 4, 5},
+	}
+	g.Metric = &graph.Metric{Cost: []float64{1, 5, 1, 1, 1, 1}}
+	g.BuildAdjlist()
+	c := g.Dense()
+
+	cost, maxCost := dijkstra(c, 0, nil)
+	// 0 -> 3 -> 4 -> 5 -> 2 avoids the expensive node
+	if cost[2] != 4 {
+		t.Errorf("Cost to node 2 should be 4, got %f", cost[2])
+	}
+	if cost[1] != 3 {
+		t.Errorf("Cost to node 1 should be 3, got %f", cost[1])
+	}
+	if maxCost != 4 {
+		t.Errorf("Max cost should be 4, got %f", maxCost)
+	}
+}
+
+func TestIsNodeConvexWeighted(t *testing.T) {
+	g := graph.NewGraph(2, 3)
+	g.Grid = [][]int{
+		{0, 1, 2},
+		{3, 4, 5},
+	}
+	g.Metric = &graph.Metric{Cost: []float64{1, 5, 1, 1, 1, 1}}
+	g.BuildAdjlist()
+	c := g.Dense()
+
+	// top row as subgraph, costs from node 0
+	costSub := []float64{0, 3, 6, -1, -1, -1}
+	if isNodeConvexWeighted(c, costSub, 3, 0, 6, nil) {
+		t.Errorf("Expected node to be non-convex, bottom row is cheaper")
+	}
+
+	// bottom row as subgraph, costs from node 3
+	costSub = []float64{-1, -1, -1, 0, 1, 2}
+	if !isNodeConvexWeighted(c, costSub, 3, 3, 2, nil) {
+		t.Errorf("Expected node to be convex")
+	}
+}
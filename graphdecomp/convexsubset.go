@@ -0,0 +1,118 @@
+package graphdecomp
+
+import (
+	"bachelor-project/graph"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Two nodes of a subset whose shortest path in the full graph is shorter than every path inside the subset
+type ConvexityWitness struct {
+	U, V           int
+	SubsetDistance float64 // -1 if V is not reachable from U inside the subset
+	GraphDistance  float64
+	Path           []int // shortest path from U to V in the full graph, it leaves the subset
+}
+
+// Returns true if nodes induce a convex subgraph of g, nodes that are not part of g are an error
+func IsConvexSubset(g *graph.Graph, nodes []int) (bool, error) {
+	witness, err := FindConvexityWitness(g, nodes)
+	return witness == nil, err
+}
+
+// Decides whether nodes induce a convex subgraph of g: the distance of every pair of nodes is the same inside the
+// subset as in g. Returns nil if the subset is convex, otherwise a witness pair. A subset that is split into several
+// components is not convex unless g is split the same way. Edge costs of g (octile, terrain) are used.
+// Only nodes with a neighbor outside of the subset are searched from, if the subset is not convex two of them are
+// a witness: a shortest path of g that leaves the subset leaves and reenters it at such nodes
+func FindConvexityWitness(g *graph.Graph, nodes []int) (*ConvexityWitness, error) {
+	for _, node := range nodes {
+		if _, exists := g.AdjList[node]; !exists {
+			return nil, fmt.Errorf("node %d is not part of the graph", node)
+		}
+	}
+	adjlist := graph.InducedAdjlist(g.AdjList, nodes)
+	exits := []int{}
+	for node, neighbors := range adjlist {
+		if len(neighbors) < len(g.AdjList[node]) {
+			exits = append(exits, node)
+		}
+	}
+	slices.Sort(exits) // deterministic witness
+
+	orig := g.Dense()
+	var sub *graph.CSR
+	if g.Weighted() {
+		sub = graph.NewWeightedCSR(adjlist, g.EdgeCost)
+	} else {
+		sub = graph.NewCSR(adjlist)
+	}
+	subBuf, origBuf := newScratch(sub.Len()), newScratch(orig.Len())
+
+	for k, u := range exits {
+		uSub, _ := sub.Index(u)
+		uOrig, _ := orig.Index(u)
+		distSub := distances(g, sub, uSub, subBuf)
+		distOrig := distances(g, orig, uOrig, origBuf)
+		// symmetric, pairs with earlier exits were checked already
+		for _, v := range exits[k+1:] {
+			vSub, _ := sub.Index(v)
+			vOrig, _ := orig.Index(v)
+			if distSub[vSub] > distOrig[vOrig]+eps {
+				subDistance := distSub[vSub]
+				if math.IsInf(subDistance, 1) {
+					subDistance = -1
+				}
+				return &ConvexityWitness{
+					U:              u,
+					V:              v,
+					SubsetDistance: subDistance,
+					GraphDistance:  distOrig[vOrig],
+					Path:           tracePath(orig, distOrig, uOrig, vOrig),
+				}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// distances from start on dense indices, +Inf for unreachable nodes
+func distances(g *graph.Graph, c *graph.CSR, start int, buf *scratch) []float64 {
+	dist := make([]float64, c.Len())
+	if g.Weighted() {
+		cost, _ := dijkstra(c, start, buf)
+		copy(dist, cost)
+		return dist
+	}
+	depth, _ := bfs(c, start, buf)
+	for i, d := range depth {
+		dist[i] = float64(d)
+		if d == -1 {
+			dist[i] = math.Inf(1)
+		}
+	}
+	return dist
+}
+
+// Returns nodeids of a shortest path from start to end, walks back from end over neighbors whose distance plus
+// edge cost equals the distance of the current node
+func tracePath(c *graph.CSR, dist []float64, start, end int) []int {
+	path := []int{c.NodeIDs[end]}
+	for current := end; current != start; {
+		weights := c.AdjacentWeights(current)
+		for k, neighbor := range c.Adjacent(current) {
+			cost := 1.0
+			if weights != nil {
+				cost = weights[k]
+			}
+			if math.Abs(dist[neighbor]+cost-dist[current]) < eps {
+				current = neighbor
+				break
+			}
+		}
+		path = append(path, c.NodeIDs[current])
+	}
+	slices.Reverse(path)
+	return path
+}
@@ -0,0 +1,109 @@
+package graphdecomp
+
+import (
+	"bachelor-project/graph"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestFindConvexityWitness(t *testing.T) {
+	/*
+		0  1  @  3
+		4  5  @  7
+		8  9  10 11
+		12 13 @  15
+	*/
+	testCases := []struct {
+		name     string
+		nodes    []int
+		expected *ConvexityWitness // path is only compared if set
+	}{
+		{"Convex", []int{0, 1, 4, 5, 8, 9, 12, 13}, nil},
+		{"Single node", []int{3}, nil},
+		{"Whole graph", []int{0, 1, 3, 4, 5, 7, 8, 9, 10, 11, 12, 13, 15}, nil},
+		{"Detour", []int{0, 1, 4, 5, 8, 12, 13}, &ConvexityWitness{U: 5, V: 13, SubsetDistance: 4, GraphDistance: 2, Path: []int{5, 9, 13}}},
+		{"Disconnected", []int{0, 15}, &ConvexityWitness{U: 0, V: 15, SubsetDistance: -1, GraphDistance: 6}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := makeTestGraph1()
+			witness, err := FindConvexityWitness(g, tc.nodes)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if convex, _ := IsConvexSubset(g, tc.nodes); convex != (tc.expected == nil) {
+				t.Errorf("Expected convex %v, got %v", tc.expected == nil, convex)
+			}
+			if tc.expected == nil {
+				if witness != nil {
+					t.Errorf("Expected convex subset, got witness %+v", witness)
+				}
+				return
+			}
+			if witness == nil {
+				t.Fatalf("Expected witness %+v, got none", tc.expected)
+			}
+			checkWitnessPath(t, g, tc.nodes, witness)
+			if tc.expected.Path == nil {
+				tc.expected.Path = witness.Path
+			}
+			if !reflect.DeepEqual(witness, tc.expected) {
+				t.Errorf("Expected witness %+v, got %+v", tc.expected, witness)
+			}
+		})
+	}
+
+	if _, err := FindConvexityWitness(makeTestGraph1(), []int{0, 2}); err == nil {
+		t.Errorf("Expected error for node outside of the graph")
+	}
+}
+
+func TestFindConvexityWitnessOctile(t *testing.T) {
+	// 3x3 open grid, subset 0 1 2 5 8 is an L along the top and right border
+	g := graph.NewGraph(3, 3)
+	g.Grid = [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}}
+	g.Metric = &graph.Metric{Octile: true}
+	g.BuildAdjlist()
+
+	nodes := []int{0, 1, 2, 5, 8}
+	witness, err := FindConvexityWitness(g, nodes)
+	if err != nil || witness == nil {
+		t.Fatalf("Expected witness, got %+v, %v", witness, err)
+	}
+	expected := &ConvexityWitness{U: 0, V: 8, SubsetDistance: 2 + math.Sqrt2, GraphDistance: 2 * math.Sqrt2, Path: []int{0, 4, 8}}
+	if witness.U != expected.U || witness.V != expected.V || !reflect.DeepEqual(witness.Path, expected.Path) ||
+		math.Abs(witness.SubsetDistance-expected.SubsetDistance) > eps || math.Abs(witness.GraphDistance-expected.GraphDistance) > eps {
+		t.Errorf("Expected witness %+v, got %+v", expected, witness)
+	}
+	checkWitnessPath(t, g, nodes, witness)
+
+	// the diagonal makes the subset convex
+	if convex, _ := IsConvexSubset(g, []int{0, 1, 2, 4, 5, 8}); !convex {
+		t.Errorf("Expected convex subset")
+	}
+}
+
+// path of witness is a path of g from U to V with the distance of g that leaves the subset
+func checkWitnessPath(t *testing.T, g *graph.Graph, nodes []int, w *ConvexityWitness) {
+	t.Helper()
+	if len(w.Path) == 0 || w.Path[0] != w.U || w.Path[len(w.Path)-1] != w.V {
+		t.Fatalf("Path %v does not connect %d and %d", w.Path, w.U, w.V)
+	}
+	length, leaves := 0.0, false
+	for i := 1; i < len(w.Path); i++ {
+		if !slices.Contains(g.AdjList[w.Path[i-1]], w.Path[i]) {
+			t.Fatalf("Path %v uses missing edge %d - %d", w.Path, w.Path[i-1], w.Path[i])
+		}
+		length += g.EdgeCost(w.Path[i-1], w.Path[i])
+		leaves = leaves || !slices.Contains(nodes, w.Path[i])
+	}
+	if math.Abs(length-w.GraphDistance) > eps {
+		t.Errorf("Path %v has length %g, expected %g", w.Path, length, w.GraphDistance)
+	}
+	if !leaves {
+		t.Errorf("Path %v does not leave the subset", w.Path)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -21,20 +22,105 @@ func main() {
 		4 =
 		use t and c only
 	*/
+	// flags are removed so positional arguments keep their index
+	args := []string{os.Args[0]}
+	search := algorithms.ShortestDistance // query algorithm of t, c and l
+	searchFlag := ""                      // flag that chose search, the query engine of l has its own search
+	workers := 0                          // l answers all scenarios as one batch with the query engine if set
+	for _, arg := range os.Args[1:] {
+		switch {
+		case arg == "--octile":
+			config.Octile = true
+		case arg == "--astar":
+			search, searchFlag = algorithms.AStar, arg
+		case arg == "--bidirectional":
+			search, searchFlag = algorithms.BidirectionalDistance, arg
+		case arg == "--separator":
+			search, searchFlag = algorithms.SeparatorDistance, arg
+		case strings.HasPrefix(arg, "--workers="):
+			parsedWorkers, err := strconv.Atoi(strings.TrimPrefix(arg, "--workers="))
+			if err != nil || parsedWorkers < 1 {
+				fmt.Println("Invalid number of workers:", arg)
+				return
+			}
+			workers = parsedWorkers
+		case strings.HasPrefix(arg, "--pipeline="):
+			if err := algorithms.SetPipeline(strings.TrimPrefix(arg, "--pipeline=")); err != nil {
+				fmt.Println("Invalid pipeline:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--pipeline-mode="):
+			if err := algorithms.SetPipelineMode(strings.TrimPrefix(arg, "--pipeline-mode=")); err != nil {
+				fmt.Println("Invalid pipeline mode:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--budget="):
+			budget, err := time.ParseDuration(strings.TrimPrefix(arg, "--budget="))
+			if err != nil || budget <= 0 {
+				fmt.Println("Invalid budget:", arg)
+				return
+			}
+			config.SelectionTime = budget
+		case strings.HasPrefix(arg, "--seed="):
+			seed, err := strconv.ParseInt(strings.TrimPrefix(arg, "--seed="), 10, 64)
+			if err != nil {
+				fmt.Println("Invalid seed:", arg)
+				return
+			}
+			config.Seed = seed
+		case strings.HasPrefix(arg, "--timeout="):
+			timeout, err := time.ParseDuration(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil || timeout <= 0 {
+				fmt.Println("Invalid timeout:", arg)
+				return
+			}
+			config.Time = timeout
+		case strings.HasPrefix(arg, "--terrain="):
+			if err := parseTerrainCosts(strings.TrimPrefix(arg, "--terrain=")); err != nil {
+				fmt.Println("Invalid terrain costs:", err)
+				return
+			}
+		default:
+			args = append(args, arg)
+		}
+	}
+	os.Args = args
+
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run main.go <mode> <benchmarkFolder> [alpha]")
+		fmt.Println("Usage: go run main.go <mode> <benchmarkFolder> [alpha] [--octile] [--terrain=S:3,...] [--astar|--bidirectional|--separator] [--workers=N] [--pipeline=osp,rowcol,...] [--pipeline-mode=sequential|portfolio|best] [--budget=60s] [--timeout=60s] [--seed=N]")
 		fmt.Println("Modes:")
 		fmt.Println("  t  = traditional BFS")
 		fmt.Println("  c  = convex benchmark")
+		fmt.Println("  s  = build hierarchy and save it: s <mapFile> <hierarchyFile> [alpha]")
+		fmt.Println("  l  = convex queries on a saved hierarchy: l <hierarchyFile> <scenarioFile>")
+		fmt.Println("  m  = distance table between all starts and goals of a scenario file: m <hierarchyFile> <scenarioFile>")
+		fmt.Println("  i  = statistics of a saved hierarchy: i <hierarchyFile> [text|json|csv]")
+		fmt.Println("  v  = check convexity and balance of every split of a saved hierarchy: v <hierarchyFile>")
 		fmt.Println("  b1 = FindDistanceBenchmarkNormal")
 		fmt.Println("  b2 = BuildGraphBenchmarkConvexNormal")
 		fmt.Println("  b3 = FindDistanceTimeNormalConvex")
 		fmt.Println("  b4 = GetSizeOfGraph")
+		fmt.Println("  b6 = HierarchyMemoryCompactNormal")
+		fmt.Println("  b7 = QueryThroughput")
+		fmt.Println("Flags:")
+		fmt.Println("  --octile = 8-connected movement with diagonal cost sqrt(2)")
+		fmt.Println("  --terrain=S:3,W:5 = passable terrain characters and their costs")
+		fmt.Println("  --astar = answer queries of t, c and l with A* instead of BFS/dijkstra")
+		fmt.Println("  --bidirectional = answer queries of t, c and l with bidirectional BFS/dijkstra")
+		fmt.Println("  --separator = answer queries of c and l through the separator of the smallest component")
+		fmt.Println("  --workers=N = answer the queries of l as one batch with N workers (not with --astar, --bidirectional or --separator), build the hierarchy of s with N workers")
+		fmt.Println("  --seed=N = seed of the random choices of heuristics, the same seed gives the same hierarchy")
+		fmt.Println("  --pipeline=kaffpa:10s,osp,rowcol = separator heuristics in order, optionally with their own time limit")
+		fmt.Println("    registered:", strings.Join(algorithms.Separators(), ", "))
+		fmt.Println("  --pipeline-mode=portfolio = run all heuristics of the pipeline at once and take the first valid decomposition")
+		fmt.Println("  --pipeline-mode=best = run all heuristics of the pipeline at once and take the valid decomposition with the lowest score")
+		fmt.Println("  --budget=60s = time budget of all heuristics of one node in best mode")
+		fmt.Println("  --timeout=60s = time limit per heuristic")
 		return
 	}
 	mode := os.Args[1]
 
-	if len(os.Args) > 2 {
+	if len(os.Args) > 3 {
 		parsedAlpha, err := strconv.ParseFloat(os.Args[3], 64)
 		if err == nil {
 			config.Alpha = parsedAlpha
@@ -48,8 +134,16 @@ func main() {
 	case "t":
 		mapPath := os.Args[2]
 		scenPath := os.Args[3]
-		g := graph.LoadGraphFromFile(mapPath)
-		scenarios := benchmark.LoadScenario(scenPath)
+		g, err := graph.LoadMap(mapPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		scenarios, err := benchmark.LoadScenarioFile(scenPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
 		for _, s := range scenarios {
 			mapWidth := s[0]
 			startX, startY := s[1], s[2]
@@ -58,7 +152,7 @@ func main() {
 			y := graph.NodeID(goalX, goalY, mapWidth)
 
 			startTime := time.Now()
-			distance := algorithms.BreadthFirstSearch(g.AdjList, x, y)
+			distance := search(g, x, y)
 			runTime := time.Since(startTime).Milliseconds()
 			fmt.Println(x, y, distance, runTime)
 		}
@@ -66,8 +160,16 @@ func main() {
 	case "c":
 		mapPath := os.Args[2]
 		scenPath := os.Args[3]
-		g := graph.LoadGraphFromFile(mapPath)
-		scenarios := benchmark.LoadScenario(scenPath)
+		g, err := graph.LoadMap(mapPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		scenarios, err := benchmark.LoadScenarioFile(scenPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
 		for _, s := range scenarios {
 			mapWidth := s[0]
 			startX, startY := s[1], s[2]
@@ -77,11 +179,196 @@ func main() {
 
 			startTime := time.Now()
 			subgraph := algorithms.FindSmallestConvexComponent(g, x, y)
-			distance := algorithms.BreadthFirstSearch(subgraph.AdjList, x, y)
+			distance := search(subgraph, x, y)
 			runTime := time.Since(startTime).Milliseconds()
 			fmt.Println(x, y, distance, runTime)
 		}
 
+	case "s":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: go run main.go s <mapFile> <hierarchyFile> [alpha]")
+			return
+		}
+		if len(os.Args) > 4 {
+			parsedAlpha, err := strconv.ParseFloat(os.Args[4], 64)
+			if err != nil {
+				fmt.Println("Invalid alpha:", os.Args[4])
+				return
+			}
+			config.Alpha = parsedAlpha
+		}
+		g, err := graph.LoadMap(os.Args[2])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		startTime := time.Now()
+		if workers > 0 {
+			algorithms.BuildConvexHierarchyParallel(g, workers)
+		} else {
+			algorithms.BuildConvexHierarchy(g)
+		}
+		buildTime := time.Since(startTime).Milliseconds()
+
+		params := graph.BuildParams{Alpha: config.Alpha, Timeout: config.Time}
+		if err := graph.SaveHierarchy(os.Args[3], g, params); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Printf("Built hierarchy in %d ms, saved to %s\n", buildTime, os.Args[3])
+
+	case "l":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: go run main.go l <hierarchyFile> <scenarioFile>")
+			return
+		}
+		if workers > 0 && searchFlag != "" {
+			fmt.Printf("%s can not be combined with --workers, the query engine answers with BFS/dijkstra\n", searchFlag)
+			fmt.Println("Usage: go run main.go l <hierarchyFile> <scenarioFile> [--workers=N | --astar|--bidirectional|--separator]")
+			return
+		}
+		startTime := time.Now()
+		g, params, err := graph.LoadHierarchy(os.Args[2])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		config.Alpha = params.Alpha
+		fmt.Printf("Loaded hierarchy (alpha %g) in %d ms\n", params.Alpha, time.Since(startTime).Milliseconds())
+
+		scenarios, err := benchmark.LoadScenarioFile(os.Args[3])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if workers > 0 {
+			queries := make([]algorithms.Query, len(scenarios))
+			for i, s := range scenarios {
+				queries[i] = algorithms.Query{Start: graph.NodeID(s[1], s[2], s[0]), End: graph.NodeID(s[3], s[4], s[0])}
+			}
+			startTime := time.Now()
+			distances := algorithms.NewQueryEngine(g, workers).Distances(queries)
+			runTime := time.Since(startTime)
+			for i, q := range queries {
+				fmt.Println(q.Start, q.End, distances[i])
+			}
+			fmt.Printf("%d queries with %d workers in %d ms (%.0f queries/s)\n", len(queries), workers, runTime.Milliseconds(), float64(len(queries))/runTime.Seconds())
+			return
+		}
+		for _, s := range scenarios {
+			mapWidth := s[0]
+			startX, startY := s[1], s[2]
+			goalX, goalY := s[3], s[4]
+			x := graph.NodeID(startX, startY, mapWidth)
+			y := graph.NodeID(goalX, goalY, mapWidth)
+
+			startTime := time.Now()
+			subgraph := algorithms.FindSmallestConvexComponent(g, x, y)
+			distance := search(subgraph, x, y)
+			runTime := time.Since(startTime).Milliseconds()
+			fmt.Println(x, y, distance, runTime)
+		}
+
+	case "m":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: go run main.go m <hierarchyFile> <scenarioFile>")
+			return
+		}
+		g, _, err := graph.LoadHierarchy(os.Args[2])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		scenarios, err := benchmark.LoadScenarioFile(os.Args[3])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		// every start and goal once, in order of appearance
+		sources, targets := []int{}, []int{}
+		seenSources, seenTargets := map[int]bool{}, map[int]bool{}
+		for _, s := range scenarios {
+			x := graph.NodeID(s[1], s[2], s[0])
+			y := graph.NodeID(s[3], s[4], s[0])
+			if !seenSources[x] {
+				seenSources[x] = true
+				sources = append(sources, x)
+			}
+			if !seenTargets[y] {
+				seenTargets[y] = true
+				targets = append(targets, y)
+			}
+		}
+
+		startTime := time.Now()
+		table := algorithms.DistanceTable(g, sources, targets)
+		runTime := time.Since(startTime).Milliseconds()
+
+		header := []string{"source"}
+		for _, target := range targets {
+			header = append(header, strconv.Itoa(target))
+		}
+		fmt.Println(strings.Join(header, " "))
+		for i, source := range sources {
+			row := []string{strconv.Itoa(source)}
+			for _, distance := range table[i] {
+				row = append(row, strconv.FormatFloat(distance, 'g', -1, 64))
+			}
+			fmt.Println(strings.Join(row, " "))
+		}
+		fmt.Printf("%dx%d table in %d ms\n", len(sources), len(targets), runTime)
+
+	case "i":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: go run main.go i <hierarchyFile> [text|json|csv]")
+			return
+		}
+		g, _, err := graph.LoadHierarchy(os.Args[2])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		stats := algorithms.NewHierarchyStats(g)
+		format := "text"
+		if len(os.Args) > 3 {
+			format = os.Args[3]
+		}
+		switch format {
+		case "text":
+			err = stats.WriteText(os.Stdout)
+		case "json":
+			err = stats.WriteJSON(os.Stdout)
+		case "csv":
+			err = stats.WriteCSV(os.Stdout)
+		default:
+			fmt.Println("Unknown format:", format)
+			return
+		}
+		if err != nil {
+			fmt.Println("Error:", err)
+		}
+
+	case "v":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: go run main.go v <hierarchyFile>")
+			return
+		}
+		g, params, err := graph.LoadHierarchy(os.Args[2])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		violations := algorithms.ValidateHierarchy(g, params.Alpha)
+		for _, v := range violations {
+			fmt.Println(v)
+		}
+		if len(violations) > 0 {
+			fmt.Printf("%d violations (alpha %g)\n", len(violations), params.Alpha)
+			os.Exit(1)
+		}
+		fmt.Printf("Hierarchy is valid (alpha %g)\n", params.Alpha)
+
 	case "b1":
 		fmt.Println("Running every heuristic for all benchmarks...")
 		for _, b := range benchmarks {
@@ -130,7 +417,42 @@ func main() {
 			fmt.Printf("Benchmarking: %s\n", b)
 			benchmark.CombinedBenchmarkConvexNormal(mapDir, scenDir, outputBasePath)
 		}
+	case "b6":
+		fmt.Println("Running hierarchy memory analysis (normal vs compact) for all benchmarks...")
+		for _, b := range benchmarks {
+			mapFolderName := b + "-map"
+			mapDir := filepath.Join("benchmark", "map", mapFolderName)
+			output := filepath.Join("benchmark", "output", b)
+			memoryCsv := filepath.Join(output + "-memory-analysis" + b)
+			benchmark.HierarchyMemoryCompactNormal(mapDir, memoryCsv)
+		}
+	case "b7":
+		fmt.Println("Running query throughput analysis for all benchmarks...")
+		for _, b := range benchmarks {
+			mapFolderName := b + "-map"
+			mapDir := filepath.Join("benchmark", "map", mapFolderName)
+			scenDir := filepath.Join("benchmark", "scen", b+"-scen")
+			output := filepath.Join("benchmark", "output", b)
+			throughputCsv := filepath.Join(output + "-throughput-analysis" + b)
+			benchmark.QueryThroughput(mapDir, scenDir, throughputCsv)
+		}
 	default:
 		fmt.Println("Unknown mode:", mode)
 	}
 }
+
+// parse terrain costs like "S:3,W:5" into config.TerrainCosts
+func parseTerrainCosts(list string) error {
+	for _, entry := range strings.Split(list, ",") {
+		char, cost, found := strings.Cut(entry, ":")
+		if !found || len(char) != 1 {
+			return fmt.Errorf("expected <character>:<cost>, got %q", entry)
+		}
+		value, err := strconv.ParseFloat(cost, 64)
+		if err != nil || value <= 0 {
+			return fmt.Errorf("cost of %q must be a positive number", char)
+		}
+		config.TerrainCosts[char[0]] = value
+	}
+	return nil
+}